@@ -0,0 +1,202 @@
+package vkads
+
+import (
+	"fmt"
+	"github.com/sintanial/vkads/vkobj"
+	"reflect"
+	"strings"
+)
+
+type SkeletonField struct {
+	Field      string
+	Required   bool
+	Definition vkobj.BannerField
+}
+
+type SkeletonUrl struct {
+	Role string
+	// ObjectTypes are the url object types accepted by the role as returned in
+	// vkobj.Package.UrlTypes.
+	ObjectTypes [][]string
+}
+
+// AdGroupSkeleton is a starting point for an ad group of a package: the ad
+// group and banner are prefilled with the package and empty pattern roles.
+// Textblocks and Content describe the pattern fields to fill in, Urls lists
+// the url roles the package accepts, which are not necessarily required.
+type AdGroupSkeleton struct {
+	Package    vkobj.Package
+	Pattern    vkobj.BannerPattern
+	AdGroup    vkobj.AdGroup
+	Banner     vkobj.Banner
+	Textblocks []SkeletonField
+	Content    []SkeletonField
+	Urls       []SkeletonUrl
+}
+
+type AdGroupBuilder struct {
+	packages []vkobj.Package
+	patterns map[int]vkobj.BannerPattern
+	fields   map[string]vkobj.BannerField
+}
+
+func NewAdGroupBuilder(packages []vkobj.Package, patterns []vkobj.BannerPattern, fields []vkobj.BannerField) *AdGroupBuilder {
+	b := &AdGroupBuilder{
+		packages: packages,
+		patterns: make(map[int]vkobj.BannerPattern),
+		fields:   make(map[string]vkobj.BannerField),
+	}
+
+	for _, pattern := range patterns {
+		b.patterns[pattern.Id] = pattern
+	}
+	for _, field := range fields {
+		b.fields[field.Field] = field
+	}
+
+	return b
+}
+
+// NewAdGroupBuilder loads the packages, banner patterns and fields.
+func (self *Api) NewAdGroupBuilder() (*AdGroupBuilder, error) {
+	packages, err := self.GetPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	patterns, err := self.GetBannerPatterns()
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := self.GetBannerFields()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAdGroupBuilder(packages.Items, patterns.Items, fields.Items), nil
+}
+
+// Packages returns the active packages supporting the objective, with the
+// banner format (any when zero) and all of the flags.
+func (b *AdGroupBuilder) Packages(objective vkobj.Objective, bannerFormatId int, flags ...string) []vkobj.Package {
+	var result []vkobj.Package
+	for _, pkg := range b.packages {
+		if pkg.Status != "" && pkg.Status != "active" {
+			continue
+		}
+
+		if bannerFormatId != 0 && pkg.BannerFormatId != bannerFormatId {
+			continue
+		}
+
+		if !containsString(pkg.Objective, string(objective)) {
+			continue
+		}
+
+		matched := true
+		for _, flag := range flags {
+			if !containsString(pkg.Flags, flag) {
+				matched = false
+			}
+		}
+
+		if matched {
+			result = append(result, pkg)
+		}
+	}
+
+	return result
+}
+
+// Build returns skeletons for every package matching the objective, banner
+// format and flags, see Packages.
+func (b *AdGroupBuilder) Build(objective vkobj.Objective, bannerFormatId int, flags ...string) ([]AdGroupSkeleton, error) {
+	packages := b.Packages(objective, bannerFormatId, flags...)
+	if len(packages) == 0 {
+		return nil, fmt.Errorf("no package for objective %q, banner format %d and flags %v", objective, bannerFormatId, flags)
+	}
+
+	var result []AdGroupSkeleton
+	for _, pkg := range packages {
+		skeleton, err := b.Skeleton(pkg, objective)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, skeleton)
+	}
+
+	return result, nil
+}
+
+func (b *AdGroupBuilder) Skeleton(pkg vkobj.Package, objective vkobj.Objective) (AdGroupSkeleton, error) {
+	pattern, ok := b.patterns[pkg.BannerFormatId]
+	if !ok {
+		return AdGroupSkeleton{}, fmt.Errorf("package %d has unknown banner pattern %d", pkg.Id, pkg.BannerFormatId)
+	}
+
+	skeleton := AdGroupSkeleton{
+		Package: pkg,
+		Pattern: pattern,
+		AdGroup: vkobj.AdGroup{
+			PackageId: pkg.Id,
+			Objective: objective,
+		},
+		Banner: vkobj.Banner{
+			Textblocks: make(map[string]vkobj.Textblock),
+			Content:    make(map[string]vkobj.BannerContent),
+			Urls:       make(map[string]vkobj.Urls),
+		},
+	}
+
+	for _, f := range pattern.Format {
+		def := b.fields[f.Field]
+		field := SkeletonField{Field: f.Field, Required: f.Required, Definition: def}
+
+		switch bannerFieldRole(f.Role, def.Role) {
+		case "textblock":
+			skeleton.Textblocks = append(skeleton.Textblocks, field)
+			skeleton.Banner.Textblocks[f.Field] = vkobj.Textblock{}
+		case "content":
+			skeleton.Content = append(skeleton.Content, field)
+			skeleton.Banner.Content[f.Field] = vkobj.BannerContent{}
+		case "url":
+			skeleton.Banner.Urls[f.Field] = vkobj.Urls{}
+		}
+	}
+
+	// only pattern url roles are prefilled, the package roles are merely
+	// accepted and ValidateBanner rejects roles outside the pattern
+	skeleton.Urls = packageUrlTypes(pkg.UrlTypes)
+
+	skeleton.AdGroup.Banners = []vkobj.Banner{skeleton.Banner}
+	return skeleton, nil
+}
+
+// packageUrlTypes returns the url roles set in the package url types.
+func packageUrlTypes(urlTypes vkobj.UrlTypes) []SkeletonUrl {
+	var result []SkeletonUrl
+
+	v := reflect.ValueOf(urlTypes)
+	for i := 0; i < v.NumField(); i++ {
+		objectTypes := v.Field(i).Interface().([][]string)
+		if len(objectTypes) == 0 {
+			continue
+		}
+
+		role := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		result = append(result, SkeletonUrl{Role: role, ObjectTypes: objectTypes})
+	}
+
+	return result
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -13,12 +13,16 @@ import (
 	"net/textproto"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Api struct {
 	token Token
 	http  *http.Client
 	debug bool
+
+	unknownFieldsHandler func(uri string, fields []string)
+	location             *time.Location
 }
 
 func New(token Token) *Api {
@@ -71,9 +75,10 @@ func NewWithHttpClient(token Token, client *http.Client) *Api {
 	}
 
 	return &Api{
-		token: token,
-		http:  client,
-		debug: true,
+		token:    token,
+		http:     client,
+		debug:    true,
+		location: time.UTC,
 	}
 }
 
@@ -81,6 +86,33 @@ func (self *Api) Debug(b bool) {
 	self.debug = b
 }
 
+// OnUnknownFields registers fn to be called when a response contains fields
+// which are not modeled by the decoded vkobj struct.
+func (self *Api) OnUnknownFields(fn func(uri string, fields []string)) {
+	self.unknownFieldsHandler = fn
+}
+
+// SetLocation sets the zone of the api timestamps, UTC by default. The api
+// sends them without a zone, set it if the timestamps of the account turn out
+// to be local.
+func (self *Api) SetLocation(loc *time.Location) {
+	self.location = loc
+}
+
+func (self *Api) Location() *time.Location {
+	return self.location
+}
+
+// Time returns the instant of the api timestamp d.
+func (self *Api) Time(d vkobj.DateTime) time.Time {
+	return d.Time(self.location)
+}
+
+// DateTime returns t as an api timestamp, e.g. for the values of filters.
+func (self *Api) DateTime(t time.Time) vkobj.DateTime {
+	return vkobj.NewDateTime(t, self.location)
+}
+
 func (self *Api) GetUser() (response vkobj.User, err error) {
 	err = self.getRequestUnmarshal("/api/v3/user.json", &response)
 	return
@@ -160,6 +192,10 @@ func (self *Api) GetAdPlans(options ...RequestOptions) Iterator[[]vkobj.AdPlan]
 	return createApiIterator[[]vkobj.AdPlan](self, "/api/v2/ad_plans.json", options...)
 }
 
+func (self *Api) GetAdPlansCursor(options ...RequestOptions) CursorIterator[vkobj.AdPlan] {
+	return createApiCursorIterator(self, "/api/v2/ad_plans.json", func(p vkobj.AdPlan) int { return p.Id }, options...)
+}
+
 type CreateAdPlanResponse struct {
 	Id int `json:"id"`
 }
@@ -169,23 +205,12 @@ func (self *Api) CreateAdPlan(adPlan vkobj.AdPlan) (response CreateAdPlanRespons
 	return
 }
 
-var AdPlanAllFieldsOption = []string{
-	"id",
-	"created",
-	"updated",
-	"name",
-	"status",
-	"vkads_status",
-	"ad_groups",
-	"autobidding_mode",
-	"budget_limit",
-	"budget_limit_day",
-	"date_start",
-	"date_end",
-	"max_price",
-	"objective",
-	"priced_goal",
-	"pricelist_id",
+var AdPlanAllFieldsOption = vkobj.AllFields[vkobj.AdPlan]()
+
+// SetAdPlanFields requests the fields of vkobj.AdPlan, unlike SetFields it doesn't
+// accept the fields of another entity.
+func (o RequestOptions) SetAdPlanFields(fields vkobj.Fields[vkobj.AdPlan]) RequestOptions {
+	return o.SetFields(fields)
 }
 
 func (self *Api) GetAdPlan(adPlanId int, options ...RequestOptions) (response vkobj.AdPlan, err error) {
@@ -279,41 +304,16 @@ func (self *Api) GetAdGroups(options ...RequestOptions) Iterator[[]vkobj.AdGroup
 	return createApiIterator[[]vkobj.AdGroup](self, "/api/v2/ad_groups.json", options...)
 }
 
-var AdGroupAllFieldsOption = []string{
-	"id",
-	"created",
-	"updated",
-	"name",
-	"status",
-	"ad_plan_id",
-	"package_id",
-	"age_restrictions",
-	"audit_pixels",
-	"autobidding_mode",
-	"banner_uniq_shows_limit",
-	"budget_limit",
-	"budget_limit_day",
-	"date_end",
-	"date_start",
-	"delivery",
-	"dynamic_banners_use_storelink",
-	"dynamic_without_remarketing",
-	"enable_offline_goals",
-	"enable_utm",
-	"issues",
-	"language",
-	"marketplace_app_client_id",
-	"max_price",
-	"objective",
-	"package_priced_event_type",
-	"price",
-	"priced_goal",
-	"pricelist_id",
-	"sk_ad_campaign_id",
-	"targetings",
-	"uniq_shows_limit",
-	"uniq_shows_period",
-	"utm",
+func (self *Api) GetAdGroupsCursor(options ...RequestOptions) CursorIterator[vkobj.AdGroup] {
+	return createApiCursorIterator(self, "/api/v2/ad_groups.json", func(g vkobj.AdGroup) int { return g.Id }, options...)
+}
+
+var AdGroupAllFieldsOption = vkobj.AllFields[vkobj.AdGroup]()
+
+// SetAdGroupFields requests the fields of vkobj.AdGroup, unlike SetFields it doesn't
+// accept the fields of another entity.
+func (o RequestOptions) SetAdGroupFields(fields vkobj.Fields[vkobj.AdGroup]) RequestOptions {
+	return o.SetFields(fields)
 }
 
 func (self *Api) GetAdGroup(adGroupId int, options ...RequestOptions) (response vkobj.AdGroup, err error) {
@@ -333,26 +333,16 @@ func (self *Api) CreateAdGroup(request vkobj.AdGroup) (response CreateAdGroupRes
 	return
 }
 
-var BannerAllFieldsOption = []string{
-	"id",
-	"created",
-	"updated",
-	"name",
-	"status",
-	"ad_group_id",
-	"content",
-	"delivery",
-	"issues",
-	"moderation_reasons",
-	"moderation_status",
-	"textblocks",
-	"urls",
+var BannerAllFieldsOption = vkobj.AllFields[vkobj.Banner]()
+
+// SetBannerFields requests the fields of vkobj.Banner, unlike SetFields it doesn't
+// accept the fields of another entity.
+func (o RequestOptions) SetBannerFields(fields vkobj.Fields[vkobj.Banner]) RequestOptions {
+	return o.SetFields(fields)
 }
 
 type BannersRequestOptions struct {
 	RequestOptions
-	AdGroupIdIn     []int
-	AdGroupStatusIn []string
 }
 
 func (o BannersRequestOptions) SetAdGroupIdIn(ids []int) BannersRequestOptions {
@@ -369,6 +359,10 @@ func (self *Api) GetBanners(options ...RequestOptions) Iterator[[]vkobj.Banner]
 	return createApiIterator[[]vkobj.Banner](self, "/api/v2/banners.json", options...)
 }
 
+func (self *Api) GetBannersCursor(options ...RequestOptions) CursorIterator[vkobj.Banner] {
+	return createApiCursorIterator(self, "/api/v2/banners.json", func(b vkobj.Banner) int { return b.Id }, options...)
+}
+
 type CreateBannerResponse vkobj.Banner
 
 func (self *Api) CreateBanner(banner vkobj.Banner) (response CreateBannerResponse, err error) {
@@ -407,10 +401,6 @@ func (self *Api) GetTargetingsTree() (response vkobj.TargetingsTreeResponse, err
 	return
 }
 
-func (self *Api) GetSegments() Iterator[[]vkobj.Segment] {
-	return createApiIterator[[]vkobj.Segment](self, "/api/v2/remarketing/segments.json")
-}
-
 func (self *Api) getRequestUnmarshal(uri string, obj interface{}, options ...RequestOptions) error {
 	u := host + uri
 	if len(options) > 0 {
@@ -436,7 +426,7 @@ func (self *Api) getRequestUnmarshal(uri string, obj interface{}, options ...Req
 		return err
 	}
 
-	return json.Unmarshal(data, obj)
+	return self.unmarshal(uri, data, obj)
 }
 
 func (self *Api) postJsonRequestUnmarshal(uri string, obj interface{}, params interface{}) error {
@@ -471,7 +461,7 @@ func (self *Api) postJsonRequestUnmarshal(uri string, obj interface{}, params in
 		return err
 	}
 
-	return json.Unmarshal(data, obj)
+	return self.unmarshal(uri, data, obj)
 }
 
 func (self *Api) postMultipartRequestUnmarshal(uri string, contentType string, params *bytes.Buffer, obj interface{}) error {
@@ -502,7 +492,41 @@ func (self *Api) postMultipartRequestUnmarshal(uri string, contentType string, p
 		return err
 	}
 
-	return json.Unmarshal(data, obj)
+	return self.unmarshal(uri, data, obj)
+}
+
+func (self *Api) deleteRequest(uri string) error {
+	req, err := http.NewRequest(http.MethodDelete, host+uri, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := self.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return self.handleError(resp)
+	}
+
+	return nil
+}
+
+func (self *Api) unmarshal(uri string, data []byte, obj interface{}) error {
+	if err := json.Unmarshal(data, obj); err != nil {
+		return err
+	}
+
+	if self.unknownFieldsHandler != nil {
+		fields, err := vkobj.UnknownFields(data, obj)
+		if err == nil && len(fields) > 0 {
+			self.unknownFieldsHandler(uri, fields)
+		}
+	}
+
+	return nil
 }
 
 func (self *Api) handleError(resp *http.Response) error {
@@ -513,12 +537,14 @@ func (self *Api) handleError(resp *http.Response) error {
 
 	var fr FailedResponse
 	if err := json.Unmarshal(data, &fr); err != nil {
-		return err
+		// non json errors come from proxies and rate limiting
+		return &ApiError{Message: resp.Status, StatusCode: resp.StatusCode}
 	}
 
 	aerr := &ApiError{
-		Code:    "",
-		Message: "",
+		Code:       "",
+		Message:    "",
+		StatusCode: resp.StatusCode,
 	}
 
 	for key, val := range fr.Error {
@@ -539,18 +565,24 @@ func (self *Api) handleError(resp *http.Response) error {
 }
 
 func createApiIterator[T any](api *Api, uri string, options ...RequestOptions) Iterator[T] {
+	// the caller's options are never modified, every request works on a copy
+	base := NewRequestOptions()
+	if len(options) > 0 {
+		base = options[0].Clone()
+	}
+
 	initialLimit := 50
-	if len(options) > 0 && options[0].GetLimit() > 0 {
-		initialLimit = options[0].GetLimit()
+	if base.GetLimit() > 0 {
+		initialLimit = base.GetLimit()
 	}
 
 	return Iterator[T]{
 		InitialLimit:  initialLimit,
-		InitialOffset: 0,
+		InitialOffset: base.GetOffset(),
 		next: func(limit int, offset int) (*Iterable[T], error) {
-			option := NewRequestOptions()
-			if len(options) > 0 {
-				option = options[0]
+			option := base.Clone()
+			if limit > 0 {
+				option.SetLimit(limit)
 			}
 			option.SetOffset(offset)
 
@@ -560,3 +592,39 @@ func createApiIterator[T any](api *Api, uri string, options ...RequestOptions) I
 		},
 	}
 }
+
+func createApiCursorIterator[E any](api *Api, uri string, id func(E) int, options ...RequestOptions) CursorIterator[E] {
+	limit := 50
+	option := NewRequestOptions()
+	if len(options) > 0 {
+		option = options[0].Clone()
+		if option.GetLimit() > 0 {
+			limit = option.GetLimit()
+		}
+	}
+
+	// the cursor is built on ids, so make sure they are returned
+	if fields := option.Get("fields"); fields != "" {
+		hasId := false
+		for _, field := range strings.Split(fields, ",") {
+			if field == "id" {
+				hasId = true
+			}
+		}
+
+		if !hasId {
+			option.Set("fields", "id,"+fields)
+		}
+	}
+
+	return CursorIterator[E]{
+		Limit:   limit,
+		options: option,
+		id:      id,
+		fetch: func(option RequestOptions) (*Iterable[[]E], error) {
+			var response Iterable[[]E]
+			err := api.getRequestUnmarshal(uri, &response, option)
+			return &response, err
+		},
+	}
+}
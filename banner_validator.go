@@ -0,0 +1,330 @@
+package vkads
+
+import (
+	"fmt"
+	"github.com/sintanial/vkads/vkobj"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type BannerViolation struct {
+	Role    string
+	Field   string
+	Message string
+}
+
+func (v BannerViolation) Error() string {
+	return v.Role + " " + v.Field + ": " + v.Message
+}
+
+type BannerViolations []BannerViolation
+
+func (v BannerViolations) Error() string {
+	var messages []string
+	for _, violation := range v {
+		messages = append(messages, violation.Error())
+	}
+	return "invalid banner: " + strings.Join(messages, "; ")
+}
+
+// BannerValidator checks banners against the pattern of their package and the
+// definitions of the pattern fields before they are sent to the api.
+type BannerValidator struct {
+	patterns map[int]vkobj.BannerPattern
+	fields   map[string]vkobj.BannerField
+	packages map[int]vkobj.Package
+}
+
+func NewBannerValidator(patterns []vkobj.BannerPattern, fields []vkobj.BannerField, packages []vkobj.Package) *BannerValidator {
+	v := &BannerValidator{
+		patterns: make(map[int]vkobj.BannerPattern),
+		fields:   make(map[string]vkobj.BannerField),
+		packages: make(map[int]vkobj.Package),
+	}
+
+	for _, pattern := range patterns {
+		v.patterns[pattern.Id] = pattern
+	}
+	for _, field := range fields {
+		v.fields[field.Field] = field
+	}
+	for _, pkg := range packages {
+		v.packages[pkg.Id] = pkg
+	}
+
+	return v
+}
+
+// NewBannerValidator loads the banner patterns, fields and packages.
+func (self *Api) NewBannerValidator() (*BannerValidator, error) {
+	patterns, err := self.GetBannerPatterns()
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := self.GetBannerFields()
+	if err != nil {
+		return nil, err
+	}
+
+	packages, err := self.GetPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBannerValidator(patterns.Items, fields.Items, packages.Items), nil
+}
+
+// Validate checks the banner of an ad group with the given package and returns
+// all violations, nil when the banner is valid.
+func (v *BannerValidator) Validate(banner vkobj.Banner, packageId int) BannerViolations {
+	pkg, ok := v.packages[packageId]
+	if !ok {
+		return BannerViolations{{Role: "package", Field: strconv.Itoa(packageId), Message: "unknown package"}}
+	}
+
+	pattern, ok := v.patterns[pkg.BannerFormatId]
+	if !ok {
+		return BannerViolations{{Role: "package", Field: strconv.Itoa(packageId), Message: fmt.Sprintf("unknown banner pattern %d", pkg.BannerFormatId)}}
+	}
+
+	return ValidateBanner(banner, pattern, v.fields)
+}
+
+// ValidateBanner checks the textblocks, content and urls of the banner against
+// the pattern and the field definitions by field name.
+func ValidateBanner(banner vkobj.Banner, pattern vkobj.BannerPattern, fields map[string]vkobj.BannerField) BannerViolations {
+	var violations BannerViolations
+	fail := func(role string, field string, format string, args ...interface{}) {
+		violations = append(violations, BannerViolation{Role: role, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	allowed := make(map[string]bool)
+	for _, f := range pattern.Format {
+		def, known := fields[f.Field]
+		role := bannerFieldRole(f.Role, def.Role)
+		allowed[role+"/"+f.Field] = true
+
+		switch role {
+		case "":
+			// unknown role, accept the field in any of the banner maps
+			_, inTextblocks := banner.Textblocks[f.Field]
+			_, inContent := banner.Content[f.Field]
+			_, inUrls := banner.Urls[f.Field]
+			if f.Required && !inTextblocks && !inContent && !inUrls {
+				fail(f.Role, f.Field, "is required")
+			}
+
+			allowed["textblock/"+f.Field] = true
+			allowed["content/"+f.Field] = true
+			allowed["url/"+f.Field] = true
+		case "textblock":
+			textblock, ok := banner.Textblocks[f.Field]
+			if !ok || textblock.Text == "" && textblock.Title == "" {
+				if f.Required {
+					fail(role, f.Field, "is required")
+				}
+				continue
+			}
+
+			if known {
+				validateTextblock(textblock, def, func(format string, args ...interface{}) { fail(role, f.Field, format, args...) })
+			}
+		case "content":
+			content, ok := banner.Content[f.Field]
+			if !ok || content.Id == 0 {
+				if f.Required {
+					fail(role, f.Field, "is required")
+				}
+				continue
+			}
+
+			if known {
+				validateContent(content, def, func(format string, args ...interface{}) { fail(role, f.Field, format, args...) })
+			}
+		case "url":
+			u, ok := banner.Urls[f.Field]
+			if !ok || u.Id == 0 && u.Url == "" {
+				if f.Required {
+					fail(role, f.Field, "is required")
+				}
+			}
+		}
+	}
+
+	for _, role := range sortedKeys(banner.Textblocks) {
+		if !allowed["textblock/"+role] {
+			fail("textblock", role, "is not part of banner pattern %q", pattern.Name)
+		}
+	}
+	for _, role := range sortedKeys(banner.Content) {
+		if !allowed["content/"+role] {
+			fail("content", role, "is not part of banner pattern %q", pattern.Name)
+		}
+	}
+	for _, role := range sortedKeys(banner.Urls) {
+		if !allowed["url/"+role] {
+			fail("url", role, "is not part of banner pattern %q", pattern.Name)
+		}
+	}
+
+	return violations
+}
+
+func bannerFieldRole(roles ...string) string {
+	for _, role := range roles {
+		switch strings.TrimSuffix(role, "s") {
+		case "textblock", "text":
+			return "textblock"
+		case "content", "image", "video":
+			return "content"
+		case "url":
+			return "url"
+		}
+	}
+	return ""
+}
+
+func validateTextblock(textblock vkobj.Textblock, def vkobj.BannerField, fail func(format string, args ...interface{})) {
+	checkLength := func(part string, value string, min int, max int) {
+		length := utf8.RuneCountInString(value)
+		if max > 0 && length > max {
+			fail("%s is %d characters long, max %d", part, length, max)
+		}
+		if min > 0 && length < min {
+			fail("%s is %d characters long, min %d", part, length, min)
+		}
+	}
+
+	// the field limits apply to the title, the text has limits of its own.
+	// Single value textblocks keep their value in the text, so it takes the
+	// field limits when there is no title and no text limit.
+	textMax, textMin := def.Format.Text.MaxLength, 0
+	if textblock.Title != "" {
+		checkLength("title", textblock.Title, def.Format.MinLength, def.Format.MaxLength)
+	} else {
+		textMin = def.Format.MinLength
+		if textMax == 0 {
+			textMax = def.Format.MaxLength
+		}
+	}
+
+	if textblock.Text != "" {
+		checkLength("text", textblock.Text, textMin, textMax)
+	}
+
+	if len(def.Format.Text.Choices) > 0 && textblock.Text != "" {
+		found := false
+		for _, choice := range def.Format.Text.Choices {
+			if choice == textblock.Text {
+				found = true
+			}
+		}
+
+		if !found {
+			fail("%q is not one of %s", textblock.Text, strings.Join(def.Format.Text.Choices, ", "))
+		}
+	}
+}
+
+func validateContent(content vkobj.BannerContent, def vkobj.BannerField, fail func(format string, args ...interface{})) {
+	if types := contentTypes(def.Format.Type); len(types) > 0 && content.Type != "" {
+		found := false
+		for _, tp := range types {
+			if tp == content.Type {
+				found = true
+			}
+		}
+
+		if !found {
+			fail("content type %q is not one of %s", content.Type, strings.Join(types, ", "))
+		}
+	}
+
+	// the variants are resized copies, the largest one is the uploaded source
+	var source vkobj.ContentVariant
+	for _, variant := range content.Variants {
+		if variant.Width*variant.Height > source.Width*source.Height {
+			source = variant
+		}
+	}
+
+	if source.Width == 0 || source.Height == 0 {
+		return
+	}
+
+	format := def.Format
+	if format.Width > 0 && format.Height > 0 && (source.Width != format.Width || source.Height != format.Height) {
+		fail("is %dx%d, must be %dx%d", source.Width, source.Height, format.Width, format.Height)
+	}
+
+	if format.MinWidth > 0 && source.Width < format.MinWidth || format.MinHeight > 0 && source.Height < format.MinHeight {
+		fail("is %dx%d, min %dx%d", source.Width, source.Height, format.MinWidth, format.MinHeight)
+	}
+
+	if format.MaxWidth > 0 && source.Width > format.MaxWidth || format.MaxHeight > 0 && source.Height > format.MaxHeight {
+		fail("is %dx%d, max %dx%d", source.Width, source.Height, format.MaxWidth, format.MaxHeight)
+	}
+
+	if format.Size > 0 && source.Size > format.Size {
+		fail("is %d bytes, max %d", source.Size, format.Size)
+	}
+
+	if ratio, ok := parseAspectRatio(format.AspectRatio); ok {
+		actual := float64(source.Width) / float64(source.Height)
+		if math.Abs(actual-ratio)/ratio > 0.01 {
+			fail("aspect ratio %.3f doesn't match %s", actual, format.AspectRatio)
+		}
+	}
+}
+
+func contentTypes(tp interface{}) []string {
+	switch v := tp.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func parseAspectRatio(s string) (float64, bool) {
+	if s == "" {
+		return 0, false
+	}
+
+	parts := strings.Split(s, ":")
+	width, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || width <= 0 {
+		return 0, false
+	}
+
+	if len(parts) == 1 {
+		return width, true
+	}
+
+	height, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || height <= 0 {
+		return 0, false
+	}
+
+	return width / height, true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
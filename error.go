@@ -1,11 +1,16 @@
 package vkads
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 type ApiError struct {
-	Code    string                 `json:"code"`
-	Message string                 `json:"message"`
-	Extra   map[string]interface{} `json:"extra"`
+	Code       string                 `json:"code"`
+	Message    string                 `json:"message"`
+	Extra      map[string]interface{} `json:"extra"`
+	StatusCode int                    `json:"-"`
 }
 
 func (a *ApiError) Error() string {
@@ -15,3 +20,10 @@ func (a *ApiError) Error() string {
 type FailedResponse struct {
 	Error map[string]interface{} `json:"error"`
 }
+
+// IsRateLimited reports whether err is an api error caused by exceeding the
+// request rate limits.
+func IsRateLimited(err error) bool {
+	var aerr *ApiError
+	return errors.As(err, &aerr) && aerr.StatusCode == http.StatusTooManyRequests
+}
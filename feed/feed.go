@@ -0,0 +1,102 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Catalog is a YML (Yandex Market Language) product feed, it is the format
+// price lists are uploaded in.
+type Catalog struct {
+	XMLName xml.Name `xml:"yml_catalog"`
+	Date    string   `xml:"date,attr"`
+	Shop    Shop     `xml:"shop"`
+}
+
+type Shop struct {
+	Name       string     `xml:"name"`
+	Company    string     `xml:"company,omitempty"`
+	Url        string     `xml:"url,omitempty"`
+	Currencies []Currency `xml:"currencies>currency"`
+	Categories []Category `xml:"categories>category"`
+	Offers     []Offer    `xml:"offers>offer"`
+}
+
+type Currency struct {
+	Id   string `xml:"id,attr"`
+	Rate string `xml:"rate,attr,omitempty"`
+}
+
+type Category struct {
+	Id       string `xml:"id,attr"`
+	ParentId string `xml:"parentId,attr,omitempty"`
+	Name     string `xml:",chardata"`
+
+	Line int `xml:"-"`
+}
+
+type Offer struct {
+	Id          string   `xml:"id,attr"`
+	Available   string   `xml:"available,attr,omitempty"`
+	Name        string   `xml:"name"`
+	Url         string   `xml:"url"`
+	Price       string   `xml:"price"`
+	OldPrice    string   `xml:"oldprice,omitempty"`
+	CurrencyId  string   `xml:"currencyId"`
+	CategoryId  string   `xml:"categoryId"`
+	Pictures    []string `xml:"picture"`
+	Vendor      string   `xml:"vendor,omitempty"`
+	Description string   `xml:"description,omitempty"`
+
+	// Line is the line of the offer in the parsed feed, zero for offers built
+	// in code.
+	Line int `xml:"-"`
+}
+
+// WriteYML writes the catalog as a YML document. An empty Date is set to the
+// current time.
+func (c Catalog) WriteYML(w io.Writer) error {
+	if c.Date == "" {
+		c.Date = time.Now().Format("2006-01-02 15:04")
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Error is a feed problem found by parsing or validation.
+type Error struct {
+	Line    int
+	OfferId string
+	Field   string
+	Message string
+}
+
+func (e Error) Error() string {
+	msg := e.Message
+	if e.Field != "" {
+		msg = e.Field + ": " + msg
+	}
+
+	if e.OfferId != "" {
+		msg = fmt.Sprintf("offer %q: %s", e.OfferId, msg)
+	}
+
+	if e.Line > 0 {
+		msg = fmt.Sprintf("line %d: %s", e.Line, msg)
+	}
+
+	return msg
+}
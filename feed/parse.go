@@ -0,0 +1,235 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"golang.org/x/net/html/charset"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// ParseYML reads a YML feed keeping the line number of every offer and
+// category. Syntax errors are returned as Error with the failing line.
+func ParseYML(r io.Reader) (*Catalog, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// russian feeds are often declared as windows-1251. The feed is converted
+	// up front, so the decoder offsets and the line numbers refer to the same
+	// bytes.
+	data, err = toUTF8(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog Catalog
+	var path []string
+	found := false
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ymlError(data, dec, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			line := lineAt(data, dec.InputOffset())
+			parent := ""
+			if len(path) > 0 {
+				parent = path[len(path)-1]
+			}
+
+			switch {
+			case t.Name.Local == "yml_catalog":
+				found = true
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "date" {
+						catalog.Date = attr.Value
+					}
+				}
+			case parent == "offers" && t.Name.Local == "offer":
+				var offer Offer
+				if err := dec.DecodeElement(&offer, &t); err != nil {
+					return nil, ymlError(data, dec, err)
+				}
+				offer.Line = line
+				catalog.Shop.Offers = append(catalog.Shop.Offers, offer)
+				continue
+			case parent == "categories" && t.Name.Local == "category":
+				var category Category
+				if err := dec.DecodeElement(&category, &t); err != nil {
+					return nil, ymlError(data, dec, err)
+				}
+				category.Line = line
+				catalog.Shop.Categories = append(catalog.Shop.Categories, category)
+				continue
+			case parent == "currencies" && t.Name.Local == "currency":
+				var currency Currency
+				if err := dec.DecodeElement(&currency, &t); err != nil {
+					return nil, ymlError(data, dec, err)
+				}
+				catalog.Shop.Currencies = append(catalog.Shop.Currencies, currency)
+				continue
+			case parent == "shop" && (t.Name.Local == "name" || t.Name.Local == "company" || t.Name.Local == "url"):
+				var value string
+				if err := dec.DecodeElement(&value, &t); err != nil {
+					return nil, ymlError(data, dec, err)
+				}
+
+				switch t.Name.Local {
+				case "name":
+					catalog.Shop.Name = value
+				case "company":
+					catalog.Shop.Company = value
+				case "url":
+					catalog.Shop.Url = value
+				}
+				continue
+			}
+
+			path = append(path, t.Name.Local)
+		case xml.EndElement:
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		}
+	}
+
+	if !found {
+		return nil, Error{Line: 1, Message: "not a yml_catalog document"}
+	}
+
+	return &catalog, nil
+}
+
+// csvColumns maps the supported CSV header names to the offer fields.
+var csvColumns = map[string]func(o *Offer, value string){
+	"id":          func(o *Offer, value string) { o.Id = value },
+	"available":   func(o *Offer, value string) { o.Available = value },
+	"name":        func(o *Offer, value string) { o.Name = value },
+	"url":         func(o *Offer, value string) { o.Url = value },
+	"price":       func(o *Offer, value string) { o.Price = value },
+	"oldprice":    func(o *Offer, value string) { o.OldPrice = value },
+	"currencyid":  func(o *Offer, value string) { o.CurrencyId = value },
+	"categoryid":  func(o *Offer, value string) { o.CategoryId = value },
+	"vendor":      func(o *Offer, value string) { o.Vendor = value },
+	"description": func(o *Offer, value string) { o.Description = value },
+	"picture": func(o *Offer, value string) {
+		for _, picture := range strings.Split(value, ",") {
+			if picture = strings.TrimSpace(picture); picture != "" {
+				o.Pictures = append(o.Pictures, picture)
+			}
+		}
+	},
+}
+
+// ParseCSV reads a CSV feed with a header row. Known columns are id,
+// available, name, url, price, oldprice, currencyId, categoryId, picture
+// (comma separated urls), vendor and description, other columns are ignored.
+func ParseCSV(r io.Reader) (*Catalog, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, Error{Line: 1, Message: "empty csv feed"}
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+
+	setters := make([]func(o *Offer, value string), len(header))
+	for i, name := range header {
+		setters[i] = csvColumns[strings.ToLower(strings.TrimSpace(name))]
+	}
+
+	var catalog Catalog
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, csvError(err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		offer := Offer{Line: line}
+		for i, value := range record {
+			if i < len(setters) && setters[i] != nil {
+				setters[i](&offer, strings.TrimSpace(value))
+			}
+		}
+
+		catalog.Shop.Offers = append(catalog.Shop.Offers, offer)
+	}
+
+	return &catalog, nil
+}
+
+// toUTF8 converts data from the encoding of its xml declaration.
+func toUTF8(data []byte) ([]byte, error) {
+	label := ""
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = func(l string, input io.Reader) (io.Reader, error) {
+		label = l
+		return input, nil
+	}
+	// the declaration is the first token, broken feeds are reported later
+	dec.Token()
+
+	if label == "" {
+		return data, nil
+	}
+
+	enc, _ := charset.Lookup(label)
+	if enc == nil {
+		return nil, Error{Line: 1, Message: fmt.Sprintf("unsupported encoding %q", label)}
+	}
+
+	converted, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, Error{Line: 1, Message: err.Error()}
+	}
+
+	return converted, nil
+}
+
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+func ymlError(data []byte, dec *xml.Decoder, err error) error {
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return Error{Line: syntaxErr.Line, Message: syntaxErr.Msg}
+	}
+
+	return Error{Line: lineAt(data, dec.InputOffset()), Message: err.Error()}
+}
+
+func csvError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return Error{Line: parseErr.Line, Message: parseErr.Err.Error()}
+	}
+
+	return err
+}
@@ -0,0 +1,231 @@
+package feed
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testYML = `<?xml version="1.0" encoding="UTF-8"?>
+<yml_catalog date="2023-05-01 10:00">
+  <shop>
+    <name>Shop</name>
+    <url>https://example.com</url>
+    <currencies>
+      <currency id="RUR" rate="1"/>
+    </currencies>
+    <categories>
+      <category id="1">Kettles</category>
+      <category id="2" parentId="1">Electric</category>
+    </categories>
+    <offers>
+      <offer id="a1" available="true">
+        <name>Kettle</name>
+        <url>https://example.com/a1</url>
+        <price>1990</price>
+        <currencyId>RUR</currencyId>
+        <categoryId>2</categoryId>
+        <picture>https://example.com/a1.jpg</picture>
+        <picture>https://example.com/a1-2.jpg</picture>
+      </offer>
+      <offer id="a2">
+        <name>Cup</name>
+        <url>https://example.com/a2</url>
+        <price>300</price>
+        <currencyId>RUR</currencyId>
+        <categoryId>1</categoryId>
+        <picture>https://example.com/a2.jpg</picture>
+      </offer>
+    </offers>
+  </shop>
+</yml_catalog>
+`
+
+func TestParseYML(t *testing.T) {
+	catalog, err := ParseYML(strings.NewReader(testYML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if catalog.Date != "2023-05-01 10:00" || catalog.Shop.Name != "Shop" || catalog.Shop.Url != "https://example.com" {
+		t.Errorf("unexpected catalog header %+v", catalog)
+	}
+	if len(catalog.Shop.Currencies) != 1 || catalog.Shop.Currencies[0].Id != "RUR" {
+		t.Errorf("unexpected currencies %+v", catalog.Shop.Currencies)
+	}
+
+	categories := catalog.Shop.Categories
+	if len(categories) != 2 || categories[1].ParentId != "1" || categories[1].Name != "Electric" || categories[1].Line != 11 {
+		t.Errorf("unexpected categories %+v", categories)
+	}
+
+	offers := catalog.Shop.Offers
+	if len(offers) != 2 {
+		t.Fatalf("got %d offers", len(offers))
+	}
+	if offers[0].Id != "a1" || offers[0].Price != "1990" || len(offers[0].Pictures) != 2 || offers[0].Line != 14 {
+		t.Errorf("unexpected first offer %+v", offers[0])
+	}
+	if offers[1].Id != "a2" || offers[1].Line != 23 {
+		t.Errorf("unexpected second offer %+v", offers[1])
+	}
+}
+
+func TestParseYMLWindows1251(t *testing.T) {
+	// "Чайник" in windows-1251
+	name := "\xd7\xe0\xe9\xed\xe8\xea"
+	feed := `<?xml version="1.0" encoding="windows-1251"?>
+<yml_catalog><shop><offers><offer id="1"><name>` + name + `</name></offer></offers></shop></yml_catalog>`
+
+	catalog, err := ParseYML(strings.NewReader(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := catalog.Shop.Offers[0].Name; got != "Чайник" {
+		t.Fatalf("name decoded as %q", got)
+	}
+}
+
+func TestParseYMLWindows1251Lines(t *testing.T) {
+	// "Чайник" in windows-1251, every letter takes two bytes in utf-8
+	name := "\xd7\xe0\xe9\xed\xe8\xea"
+	feed := `<?xml version="1.0" encoding="windows-1251"?>
+<yml_catalog>
+<shop>
+<name>` + name + `</name>
+<company>` + name + ` ` + name + `</company>
+<offers>
+<offer id="1">
+<name>` + name + `</name>
+</offer>
+<offer id="2">
+<name>` + name + `</name>
+</offer>
+</offers>
+</shop>
+</yml_catalog>`
+
+	catalog, err := ParseYML(strings.NewReader(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offers := catalog.Shop.Offers
+	if len(offers) != 2 || offers[0].Line != 7 || offers[1].Line != 10 {
+		t.Fatalf("unexpected offers %+v", offers)
+	}
+
+	_, err = ParseYML(strings.NewReader(strings.Replace(feed, "</offer>\n<offer id=\"2\">", "</offer>\n<offer id=\"2\"", 1)))
+	var feedErr Error
+	if !errors.As(err, &feedErr) || feedErr.Line != 11 {
+		t.Fatalf("got %v, want an error at line 11", err)
+	}
+}
+
+func TestParseYMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		feed string
+		line int
+	}{
+		{name: "not a catalog", feed: "<?xml version=\"1.0\"?>\n<rss></rss>", line: 1},
+		{name: "unclosed element", feed: "<yml_catalog>\n<shop>\n<offers>\n</shop>\n</yml_catalog>", line: 4},
+		{name: "broken offer", feed: "<yml_catalog>\n<shop>\n<offers>\n<offer id=\"1\">\n<name>x</nam>\n</offer>\n</offers>\n</shop>\n</yml_catalog>", line: 5},
+		{name: "unknown encoding", feed: "<?xml version=\"1.0\" encoding=\"x-unknown\"?>\n<yml_catalog/>", line: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseYML(strings.NewReader(test.feed))
+
+			var feedErr Error
+			if !errors.As(err, &feedErr) {
+				t.Fatalf("got %v, want a feed Error", err)
+			}
+			if feedErr.Line != test.line {
+				t.Fatalf("got line %d, want %d: %v", feedErr.Line, test.line, err)
+			}
+		})
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	feed := "id,Name,price,currencyId,categoryId,picture,extra\n" +
+		"1,Kettle,1990,RUR,2,\"https://example.com/1.jpg, https://example.com/2.jpg\",x\n" +
+		"\"2\",\"Cup\nwith lid\",300,RUR,1,https://example.com/3.jpg,y\n" +
+		"3,Spoon,50\n"
+
+	catalog, err := ParseCSV(strings.NewReader(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offers := catalog.Shop.Offers
+	if len(offers) != 3 {
+		t.Fatalf("got %d offers", len(offers))
+	}
+
+	tests := []struct {
+		id       string
+		name     string
+		pictures int
+		line     int
+	}{
+		{id: "1", name: "Kettle", pictures: 2, line: 2},
+		{id: "2", name: "Cup\nwith lid", pictures: 1, line: 3},
+		{id: "3", name: "Spoon", pictures: 0, line: 5},
+	}
+
+	for i, test := range tests {
+		offer := offers[i]
+		if offer.Id != test.id || offer.Name != test.name || len(offer.Pictures) != test.pictures || offer.Line != test.line {
+			t.Errorf("offer %d is %+v", i, offer)
+		}
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		feed string
+		line int
+	}{
+		{name: "empty", feed: "", line: 1},
+		{name: "bare quote", feed: "id,name\n1,ok\n2,bad\"quote\n", line: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseCSV(strings.NewReader(test.feed))
+
+			var feedErr Error
+			if !errors.As(err, &feedErr) {
+				t.Fatalf("got %v, want a feed Error", err)
+			}
+			if feedErr.Line != test.line {
+				t.Fatalf("got line %d, want %d: %v", feedErr.Line, test.line, err)
+			}
+		})
+	}
+}
+
+func TestWriteYML(t *testing.T) {
+	catalog, err := ParseYML(strings.NewReader(testYML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := catalog.WriteYML(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseYML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Shop.Offers) != 2 || parsed.Shop.Offers[1].Name != "Cup" || parsed.Date != catalog.Date {
+		t.Fatalf("round trip gave %+v", parsed)
+	}
+}
@@ -0,0 +1,111 @@
+package feed
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Validate checks the offers of the catalog and returns all found problems.
+// Every offer needs a unique id, a name, an absolute url, a positive price
+// and at least one picture. Currency and category references are checked
+// when the catalog declares currencies and categories.
+func Validate(c *Catalog) []Error {
+	var errs []Error
+
+	currencies := make(map[string]bool)
+	for _, currency := range c.Shop.Currencies {
+		currencies[currency.Id] = true
+	}
+
+	categories := make(map[string]bool)
+	for _, category := range c.Shop.Categories {
+		if category.Id == "" {
+			errs = append(errs, Error{Line: category.Line, Field: "category", Message: "id is required"})
+			continue
+		}
+		categories[category.Id] = true
+	}
+
+	if len(c.Shop.Offers) == 0 {
+		errs = append(errs, Error{Message: "feed has no offers"})
+	}
+
+	seen := make(map[string]int)
+	for _, offer := range c.Shop.Offers {
+		fail := func(field string, message string) {
+			errs = append(errs, Error{Line: offer.Line, OfferId: offer.Id, Field: field, Message: message})
+		}
+
+		if offer.Id == "" {
+			fail("id", "is required")
+		} else if line, ok := seen[offer.Id]; ok {
+			fail("id", "duplicates the offer at line "+strconv.Itoa(line))
+		} else {
+			seen[offer.Id] = offer.Line
+		}
+
+		if strings.TrimSpace(offer.Name) == "" {
+			fail("name", "is required")
+		}
+
+		if offer.Url == "" {
+			fail("url", "is required")
+		} else if !isHttpUrl(offer.Url) {
+			fail("url", "is not an absolute http(s) url")
+		}
+
+		price, ok := parsePrice(offer.Price)
+		if offer.Price == "" {
+			fail("price", "is required")
+		} else if !ok || price <= 0 {
+			fail("price", "must be a positive number")
+		}
+
+		if offer.OldPrice != "" {
+			oldPrice, oldOk := parsePrice(offer.OldPrice)
+			if !oldOk {
+				fail("oldprice", "must be a number")
+			} else if ok && oldPrice <= price {
+				fail("oldprice", "must be greater than price")
+			}
+		}
+
+		if offer.CurrencyId == "" {
+			fail("currencyId", "is required")
+		} else if len(currencies) > 0 && !currencies[offer.CurrencyId] {
+			fail("currencyId", "references unknown currency "+strconv.Quote(offer.CurrencyId))
+		}
+
+		if offer.CategoryId == "" {
+			fail("categoryId", "is required")
+		} else if len(categories) > 0 && !categories[offer.CategoryId] {
+			fail("categoryId", "references unknown category "+strconv.Quote(offer.CategoryId))
+		}
+
+		if len(offer.Pictures) == 0 {
+			fail("picture", "is required")
+		}
+		for _, picture := range offer.Pictures {
+			if !isHttpUrl(picture) {
+				fail("picture", strconv.Quote(picture)+" is not an absolute http(s) url")
+			}
+		}
+	}
+
+	return errs
+}
+
+func isHttpUrl(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func parsePrice(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(s), ",", ".", 1), 64)
+	return f, err == nil
+}
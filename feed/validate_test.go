@@ -0,0 +1,127 @@
+package feed
+
+import (
+	"strings"
+	"testing"
+)
+
+func validOffer(id string) Offer {
+	return Offer{
+		Id:         id,
+		Name:       "Kettle",
+		Url:        "https://example.com/" + id,
+		Price:      "1990",
+		CurrencyId: "RUR",
+		CategoryId: "1",
+		Pictures:   []string{"https://example.com/" + id + ".jpg"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Catalog)
+		want   []string
+	}{
+		{
+			name:   "valid",
+			modify: func(c *Catalog) {},
+		},
+		{
+			name:   "no offers",
+			modify: func(c *Catalog) { c.Shop.Offers = nil },
+			want:   []string{"feed has no offers"},
+		},
+		{
+			name: "missing fields",
+			modify: func(c *Catalog) {
+				c.Shop.Offers[0] = Offer{Id: "a", Line: 7}
+			},
+			want: []string{
+				`line 7: offer "a": name: is required`,
+				`line 7: offer "a": url: is required`,
+				`line 7: offer "a": price: is required`,
+				`line 7: offer "a": currencyId: is required`,
+				`line 7: offer "a": categoryId: is required`,
+				`line 7: offer "a": picture: is required`,
+			},
+		},
+		{
+			name: "duplicate id",
+			modify: func(c *Catalog) {
+				c.Shop.Offers[0].Line = 3
+				c.Shop.Offers[1].Id = "a"
+				c.Shop.Offers[1].Line = 9
+			},
+			want: []string{`line 9: offer "a": id: duplicates the offer at line 3`},
+		},
+		{
+			name: "bad values",
+			modify: func(c *Catalog) {
+				c.Shop.Offers[0].Url = "/relative"
+				c.Shop.Offers[0].Price = "0"
+				c.Shop.Offers[0].Pictures = []string{"ftp://example.com/a.jpg"}
+				c.Shop.Offers[1].Price = "10,5"
+				c.Shop.Offers[1].OldPrice = "10"
+			},
+			want: []string{
+				`offer "a": url: is not an absolute http(s) url`,
+				`offer "a": price: must be a positive number`,
+				`offer "a": picture: "ftp://example.com/a.jpg" is not an absolute http(s) url`,
+				`offer "b": oldprice: must be greater than price`,
+			},
+		},
+		{
+			name: "unknown references",
+			modify: func(c *Catalog) {
+				c.Shop.Offers[0].CurrencyId = "USD"
+				c.Shop.Offers[1].CategoryId = "9"
+			},
+			want: []string{
+				`offer "a": currencyId: references unknown currency "USD"`,
+				`offer "b": categoryId: references unknown category "9"`,
+			},
+		},
+		{
+			name: "references unchecked without declarations",
+			modify: func(c *Catalog) {
+				c.Shop.Currencies = nil
+				c.Shop.Categories = nil
+				c.Shop.Offers[0].CurrencyId = "USD"
+				c.Shop.Offers[0].CategoryId = "9"
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			catalog := &Catalog{Shop: Shop{
+				Currencies: []Currency{{Id: "RUR"}},
+				Categories: []Category{{Id: "1", Name: "Kettles"}},
+				Offers:     []Offer{validOffer("a"), validOffer("b")},
+			}}
+			test.modify(catalog)
+
+			var got []string
+			for _, err := range Validate(catalog) {
+				got = append(got, err.Error())
+			}
+
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Fatalf("got errors\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestValidateParsedLines(t *testing.T) {
+	catalog, err := ParseYML(strings.NewReader(strings.Replace(testYML, "<price>300</price>", "<price>free</price>", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := Validate(catalog)
+	if len(errs) != 1 || errs[0].Line != 23 || errs[0].Field != "price" {
+		t.Fatalf("got %v", errs)
+	}
+}
@@ -0,0 +1,180 @@
+package vkads
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type FilterOperator string
+
+const FilterOperatorEq FilterOperator = ""
+const FilterOperatorIn FilterOperator = "__in"
+const FilterOperatorNe FilterOperator = "__ne"
+const FilterOperatorGt FilterOperator = "__gt"
+const FilterOperatorLt FilterOperator = "__lt"
+const FilterOperatorContains FilterOperator = "__contains"
+
+// Filter builds the _field__operator and _sorting query parameters of list
+// endpoints. Field names are checked against the fields known for the entity,
+// the first unknown field is reported by Options.
+type Filter struct {
+	fields  map[string]bool
+	values  map[string]string
+	sorting []string
+	err     error
+}
+
+func NewFilter(fields ...[]string) *Filter {
+	f := &Filter{
+		fields: make(map[string]bool),
+		values: make(map[string]string),
+	}
+
+	for _, list := range fields {
+		for _, field := range list {
+			f.fields[field] = true
+		}
+	}
+
+	return f
+}
+
+func NewAdPlanFilter() *Filter {
+	return NewFilter(AdPlanAllFieldsOption)
+}
+
+func NewAdGroupFilter() *Filter {
+	return NewFilter(AdGroupAllFieldsOption)
+}
+
+func NewBannerFilter() *Filter {
+	return NewFilter(BannerAllFieldsOption, []string{"ad_group_status"})
+}
+
+func (f *Filter) Where(field string, op FilterOperator, values ...interface{}) *Filter {
+	if !f.check(field) {
+		return f
+	}
+
+	var formatted []string
+	for _, val := range flattenFilterValues(values) {
+		// the zone of api timestamps is a setting of the Api
+		if _, ok := val.(time.Time); ok {
+			if f.err == nil {
+				f.err = fmt.Errorf("filter field %q: pass time values as Api.DateTime(t)", field)
+			}
+			return f
+		}
+
+		formatted = append(formatted, formatFilterValue(val))
+	}
+
+	f.values["_"+field+string(op)] = strings.Join(formatted, ",")
+	return f
+}
+
+func (f *Filter) Eq(field string, value interface{}) *Filter {
+	return f.Where(field, FilterOperatorEq, value)
+}
+
+func (f *Filter) In(field string, values ...interface{}) *Filter {
+	return f.Where(field, FilterOperatorIn, values...)
+}
+
+func (f *Filter) Ne(field string, value interface{}) *Filter {
+	return f.Where(field, FilterOperatorNe, value)
+}
+
+func (f *Filter) Gt(field string, value interface{}) *Filter {
+	return f.Where(field, FilterOperatorGt, value)
+}
+
+func (f *Filter) Lt(field string, value interface{}) *Filter {
+	return f.Where(field, FilterOperatorLt, value)
+}
+
+func (f *Filter) Contains(field string, value interface{}) *Filter {
+	return f.Where(field, FilterOperatorContains, value)
+}
+
+func (f *Filter) SortAsc(field string) *Filter {
+	if f.check(field) {
+		f.sorting = append(f.sorting, field)
+	}
+	return f
+}
+
+func (f *Filter) SortDesc(field string) *Filter {
+	if f.check(field) {
+		f.sorting = append(f.sorting, "-"+field)
+	}
+	return f
+}
+
+func (f *Filter) Options() (RequestOptions, error) {
+	return f.Apply(NewRequestOptions())
+}
+
+// Apply returns a copy of options extended with the filter parameters.
+func (f *Filter) Apply(options RequestOptions) (RequestOptions, error) {
+	if f.err != nil {
+		return options, f.err
+	}
+
+	o := options.Clone()
+	for key, val := range f.values {
+		o.Set(key, val)
+	}
+
+	if len(f.sorting) > 0 {
+		o.SetSorting(f.sorting)
+	}
+
+	return o, nil
+}
+
+func (f *Filter) check(field string) bool {
+	if f.fields[field] {
+		return true
+	}
+
+	if f.err == nil {
+		f.err = fmt.Errorf("unknown filter field %q", field)
+	}
+	return false
+}
+
+func flattenFilterValues(values []interface{}) []interface{} {
+	var result []interface{}
+	for _, val := range values {
+		rv := reflect.ValueOf(val)
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+			for i := 0; i < rv.Len(); i++ {
+				result = append(result, rv.Index(i).Interface())
+			}
+			continue
+		}
+
+		result = append(result, val)
+	}
+
+	return result
+}
+
+func formatFilterValue(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,23 @@
+package vkads
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterRejectsTime(t *testing.T) {
+	api := New(Token{})
+	api.SetLocation(time.FixedZone("MSK", 3*60*60))
+
+	option, err := NewAdPlanFilter().Gt("updated", api.DateTime(time.Date(2023, 5, 1, 21, 0, 0, 0, time.UTC))).Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if option.Get("_updated__gt") != "2023-05-02 00:00:00" {
+		t.Fatalf("got %v", option.Values)
+	}
+
+	if _, err := NewAdPlanFilter().Gt("updated", time.Now()).Options(); err == nil {
+		t.Fatal("time.Time without a zone was accepted")
+	}
+}
@@ -4,7 +4,8 @@ go 1.18
 
 require (
 	github.com/gabriel-vasile/mimetype v1.4.2
+	golang.org/x/net v0.8.0
 	gopkg.in/vansante/go-ffprobe.v2 v2.1.1
 )
 
-require golang.org/x/net v0.8.0 // indirect
+require golang.org/x/text v0.8.0 // indirect
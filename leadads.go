@@ -0,0 +1,108 @@
+package vkads
+
+import (
+	"encoding/csv"
+	"github.com/sintanial/vkads/vkobj"
+	"io"
+	"strconv"
+	"time"
+)
+
+func (self *Api) GetLeadForms(options ...RequestOptions) Iterator[[]vkobj.LeadForm] {
+	return createApiIterator[[]vkobj.LeadForm](self, "/api/v2/lead_ads/lead_forms.json", options...)
+}
+
+func (self *Api) GetLeadForm(formId int, options ...RequestOptions) (response vkobj.LeadForm, err error) {
+	err = self.getRequestUnmarshal("/api/v2/lead_ads/lead_forms/"+strconv.Itoa(formId)+".json", &response, options...)
+	return
+}
+
+func (self *Api) CreateLeadForm(form vkobj.LeadForm) (response vkobj.LeadForm, err error) {
+	err = self.postJsonRequestUnmarshal("/api/v2/lead_ads/lead_forms.json", &response, form)
+	return
+}
+
+// GetLeads lists the leads submitted through the form in [from, to) ordered by
+// submission time. Zero from or to leaves the range open.
+func (self *Api) GetLeads(formId int, from time.Time, to time.Time, options ...RequestOptions) Iterator[[]vkobj.Lead] {
+	option := NewRequestOptions()
+	if len(options) > 0 {
+		option = options[0].Clone()
+	}
+
+	if !from.IsZero() {
+		option.Set("_created__gt", self.DateTime(from.Add(-time.Second)).String())
+	}
+	if !to.IsZero() {
+		option.Set("_created__lt", self.DateTime(to).String())
+	}
+	option.SetSorting([]string{"created", "id"})
+
+	return createApiIterator[[]vkobj.Lead](self, "/api/v2/lead_ads/lead_forms/"+strconv.Itoa(formId)+"/leads.json", option)
+}
+
+// WriteLeadsCSV writes the leads as CSV with a header row, submission times
+// are read in the api zone and converted to loc (UTC when nil). The answer columns follow the form
+// fields, answers to keys missing from the form are appended in order of
+// appearance.
+func (self *Api) WriteLeadsCSV(w io.Writer, form vkobj.LeadForm, leads []vkobj.Lead, loc *time.Location) error {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var keys []string
+	header := []string{"id", "form_id", "ad_plan_id", "ad_group_id", "banner_id", "created"}
+	known := make(map[string]bool)
+	for _, field := range form.Fields {
+		keys = append(keys, field.Key)
+		known[field.Key] = true
+
+		title := field.Title
+		if title == "" {
+			title = field.Key
+		}
+		header = append(header, title)
+	}
+
+	for _, lead := range leads {
+		for _, answer := range lead.Answers {
+			if !known[answer.Key] {
+				keys = append(keys, answer.Key)
+				known[answer.Key] = true
+				header = append(header, answer.Key)
+			}
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, lead := range leads {
+		created := ""
+		if lead.Created != nil {
+			created = self.Time(*lead.Created).In(loc).Format("2006-01-02 15:04:05")
+		}
+
+		record := []string{
+			strconv.Itoa(lead.Id),
+			strconv.Itoa(lead.FormId),
+			strconv.Itoa(lead.AdPlanId),
+			strconv.Itoa(lead.AdGroupId),
+			strconv.Itoa(lead.BannerId),
+			created,
+		}
+
+		for _, key := range keys {
+			record = append(record, lead.Answer(key))
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,40 @@
+package vkads
+
+import (
+	"bytes"
+	"github.com/sintanial/vkads/vkobj"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLeadsUseApiLocation(t *testing.T) {
+	entities := newFakeEntities()
+	api := entities.api()
+	api.SetLocation(time.FixedZone("MSK", 3*60*60))
+
+	from := time.Date(2023, 5, 1, 21, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	it := api.GetLeads(1, from, to)
+	if _, err := it.All(); err != nil {
+		t.Fatal(err)
+	}
+
+	query := entities.queries[0]
+	if query.Get("_created__gt") != "2023-05-01 23:59:59" || query.Get("_created__lt") != "2023-05-02 01:00:00" {
+		t.Fatalf("unexpected range in %v", query)
+	}
+
+	created, _ := vkobj.ParseDateTime("2023-05-02 00:30:00")
+	leads := []vkobj.Lead{{Id: 7, Created: &created}, {Id: 8}}
+
+	var buf bytes.Buffer
+	if err := api.WriteLeadsCSV(&buf, vkobj.LeadForm{}, leads, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if lines[1] != "7,0,0,0,0,2023-05-01 21:30:00" || lines[2] != "8,0,0,0,0," {
+		t.Fatalf("got csv\n%s", buf.String())
+	}
+}
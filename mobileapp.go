@@ -0,0 +1,73 @@
+package vkads
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sintanial/vkads/vkobj"
+	"strconv"
+)
+
+func (self *Api) GetMobileApps(options ...RequestOptions) Iterator[[]vkobj.MobileApp] {
+	return createApiIterator[[]vkobj.MobileApp](self, "/api/v2/mobile_apps.json", options...)
+}
+
+func (self *Api) GetMobileApp(appId int, options ...RequestOptions) (response vkobj.MobileApp, err error) {
+	err = self.getRequestUnmarshal("/api/v2/mobile_apps/"+strconv.Itoa(appId)+".json", &response, options...)
+	return
+}
+
+// RegisterMobileApp registers an app by its store url, the api detects the
+// platform and bundle id.
+func (self *Api) RegisterMobileApp(app vkobj.MobileApp) (response vkobj.MobileApp, err error) {
+	err = self.postJsonRequestUnmarshal("/api/v2/mobile_apps.json", &response, app)
+	return
+}
+
+// ValidateAppAdGroup checks that an app campaign ad group promotes app with a
+// package supporting the group objective and the app store url types.
+func ValidateAppAdGroup(group vkobj.AdGroup, pkg vkobj.Package, app vkobj.MobileApp) error {
+	if group.PackageId != 0 && group.PackageId != pkg.Id {
+		return fmt.Errorf("ad group package %d doesn't match package %d", group.PackageId, pkg.Id)
+	}
+
+	if group.Objective != vkobj.ObjectiveAppInstalls && group.Objective != vkobj.ObjectiveInAppConversions {
+		return fmt.Errorf("objective %q is not an app objective", group.Objective)
+	}
+
+	supported := false
+	for _, objective := range pkg.Objective {
+		if objective == string(group.Objective) {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("package %d doesn't support objective %q", pkg.Id, group.Objective)
+	}
+
+	if group.MarketplaceAppClientId == 0 {
+		return errors.New("marketplace_app_client_id must be set for app campaigns")
+	}
+	if app.Id != 0 && group.MarketplaceAppClientId != app.Id {
+		return fmt.Errorf("marketplace_app_client_id %d doesn't match app %d", group.MarketplaceAppClientId, app.Id)
+	}
+
+	storeUrls := pkg.UrlTypes.AndroidStoreUrl
+	if app.Platform == vkobj.MobileAppPlatformIos {
+		storeUrls = pkg.UrlTypes.IosStoreUrl
+	}
+	if len(storeUrls) == 0 {
+		return fmt.Errorf("package %d doesn't accept %s urls", pkg.Id, app.StoreUrlType())
+	}
+
+	if group.SkAdCampaignId != nil {
+		if app.Platform != vkobj.MobileAppPlatformIos {
+			return errors.New("sk_ad_campaign_id is only allowed for ios apps")
+		}
+
+		if *group.SkAdCampaignId < vkobj.MinSkAdCampaignId || *group.SkAdCampaignId > vkobj.MaxSkAdCampaignId {
+			return fmt.Errorf("sk_ad_campaign_id must be between %d and %d", vkobj.MinSkAdCampaignId, vkobj.MaxSkAdCampaignId)
+		}
+	}
+
+	return nil
+}
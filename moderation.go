@@ -0,0 +1,167 @@
+package vkads
+
+import (
+	"context"
+	"errors"
+	"github.com/sintanial/vkads/vkobj"
+	"strconv"
+	"time"
+)
+
+type ModerationEvent struct {
+	BannerId  int
+	AdGroupId int
+	From      vkobj.ModerationStatus
+	To        vkobj.ModerationStatus
+	Banner    vkobj.Banner
+	Time      time.Time
+}
+
+// ModerationWatcher polls the moderation status of banners, selected by
+// BannerIds or by AdGroupIds, and reports status transitions. The first poll
+// only records the current statuses.
+type ModerationWatcher struct {
+	BannerIds  []int
+	AdGroupIds []int
+	// Interval is the delay between polls, rate limited polls are retried
+	// with a doubled delay up to MaxInterval.
+	Interval    time.Duration
+	MaxInterval time.Duration
+	// RequestInterval is the minimal delay between page requests of a poll.
+	RequestInterval time.Duration
+
+	api      *Api
+	statuses map[int]vkobj.ModerationStatus
+}
+
+func (self *Api) NewModerationWatcher(bannerIds []int, adGroupIds []int, interval time.Duration) *ModerationWatcher {
+	return &ModerationWatcher{
+		BannerIds:   bannerIds,
+		AdGroupIds:  adGroupIds,
+		Interval:    interval,
+		MaxInterval: 16 * interval,
+		api:         self,
+		statuses:    make(map[int]vkobj.ModerationStatus),
+	}
+}
+
+var moderationBannerFields = vkobj.MustSelectFields[vkobj.Banner]("id", "ad_group_id", "status", "moderation_status", "moderation_reasons")
+
+// Poll fetches the banners once and returns the transitions since the
+// previous poll.
+func (w *ModerationWatcher) Poll() ([]ModerationEvent, error) {
+	option := BannersRequestOptions{NewRequestOptions().
+		SetBannerFields(moderationBannerFields).
+		SetLimit(250)}
+	if len(w.BannerIds) > 0 {
+		option.SetIdIn(w.BannerIds)
+	}
+	if len(w.AdGroupIds) > 0 {
+		option.SetAdGroupIdIn(w.AdGroupIds)
+	}
+
+	it := w.api.GetBanners(option.RequestOptions)
+	it.RequestInterval = w.RequestInterval
+
+	// statuses are stored only once every page is fetched, so a failed poll
+	// is retried with the same baseline and no transition is lost
+	var events []ModerationEvent
+	statuses := make(map[int]vkobj.ModerationStatus)
+	now := time.Now()
+	for it.HasNext() {
+		page, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		if page == nil {
+			break
+		}
+
+		for _, banner := range page.Items {
+			previous, known := w.statuses[banner.Id]
+			statuses[banner.Id] = banner.ModerationStatus
+
+			if known && previous != banner.ModerationStatus {
+				events = append(events, ModerationEvent{
+					BannerId:  banner.Id,
+					AdGroupId: banner.AdGroupId,
+					From:      previous,
+					To:        banner.ModerationStatus,
+					Banner:    banner,
+					Time:      now,
+				})
+			}
+		}
+	}
+
+	for id, status := range statuses {
+		w.statuses[id] = status
+	}
+
+	return events, nil
+}
+
+// Status returns the last seen moderation status of the banner.
+func (w *ModerationWatcher) Status(bannerId int) (vkobj.ModerationStatus, bool) {
+	status, ok := w.statuses[bannerId]
+	return status, ok
+}
+
+// Run polls until ctx is done and calls fn for every transition. Errors other
+// than rate limiting stop the watcher.
+func (w *ModerationWatcher) Run(ctx context.Context, fn func(ModerationEvent)) error {
+	if w.Interval <= 0 {
+		return errors.New("moderation watcher interval must be positive")
+	}
+
+	interval := w.Interval
+	for {
+		events, err := w.Poll()
+		if err != nil && !IsRateLimited(err) {
+			return err
+		}
+
+		if err != nil {
+			interval *= 2
+			if w.MaxInterval > 0 && interval > w.MaxInterval {
+				interval = w.MaxInterval
+			}
+		} else {
+			interval = w.Interval
+		}
+
+		for _, event := range events {
+			fn(event)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Watch runs the watcher in a goroutine and delivers transitions through the
+// returned channel, which is closed when the watcher stops. The error channel
+// receives the reason of the stop.
+func (w *ModerationWatcher) Watch(ctx context.Context) (<-chan ModerationEvent, <-chan error) {
+	events := make(chan ModerationEvent)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(events)
+		errs <- w.Run(ctx, func(event ModerationEvent) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return events, errs
+}
+
+func (e ModerationEvent) String() string {
+	return "banner " + strconv.Itoa(e.BannerId) + ": " + string(e.From) + " -> " + string(e.To)
+}
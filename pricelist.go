@@ -0,0 +1,100 @@
+package vkads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/sintanial/vkads/vkobj"
+	"io"
+	"mime/multipart"
+	"strconv"
+	"time"
+)
+
+func (self *Api) GetPricelists(options ...RequestOptions) Iterator[[]vkobj.Pricelist] {
+	return createApiIterator[[]vkobj.Pricelist](self, "/api/v2/pricelists.json", options...)
+}
+
+func (self *Api) GetPricelist(pricelistId int, options ...RequestOptions) (response vkobj.Pricelist, err error) {
+	err = self.getRequestUnmarshal("/api/v2/pricelists/"+strconv.Itoa(pricelistId)+".json", &response, options...)
+	return
+}
+
+// CreatePricelist creates a price list loaded by the api from pricelist.Url.
+func (self *Api) CreatePricelist(pricelist vkobj.Pricelist) (response vkobj.Pricelist, err error) {
+	err = self.postJsonRequestUnmarshal("/api/v2/pricelists.json", &response, pricelist)
+	return
+}
+
+// UploadPricelist creates a price list from a YML or CSV feed read from r.
+func (self *Api) UploadPricelist(name string, format vkobj.PricelistFormat, feed io.Reader) (response vkobj.Pricelist, err error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	data, err := json.Marshal(map[string]string{
+		"name":   name,
+		"format": string(format),
+	})
+	if err != nil {
+		return response, err
+	}
+
+	if err = w.WriteField("data", string(data)); err != nil {
+		return response, err
+	}
+
+	filePart, err := w.CreateFormFile("file", "feed."+string(format))
+	if err != nil {
+		return response, err
+	}
+
+	if _, err = io.Copy(filePart, feed); err != nil {
+		return response, err
+	}
+
+	if err = w.Close(); err != nil {
+		return response, err
+	}
+
+	err = self.postMultipartRequestUnmarshal("/api/v2/pricelists.json", w.FormDataContentType(), &buf, &response)
+	return
+}
+
+func (self *Api) UpdatePricelist(pricelistId int, pricelist vkobj.Pricelist) (response vkobj.Pricelist, err error) {
+	err = self.postJsonRequestUnmarshal("/api/v2/pricelists/"+strconv.Itoa(pricelistId)+".json", &response, pricelist)
+	return
+}
+
+// RefreshPricelist asks the api to reload the feed without waiting for the
+// refresh period.
+func (self *Api) RefreshPricelist(pricelistId int) error {
+	return self.postJsonRequestUnmarshal("/api/v2/pricelists/"+strconv.Itoa(pricelistId)+"/refresh.json", nil, struct{}{})
+}
+
+func (self *Api) DeletePricelist(pricelistId int) error {
+	return self.deleteRequest("/api/v2/pricelists/" + strconv.Itoa(pricelistId) + ".json")
+}
+
+// WaitPricelist polls the price list every interval until the feed is
+// processed or ctx is done. Feed problems are reported in Pricelist.Errors.
+func (self *Api) WaitPricelist(ctx context.Context, pricelistId int, interval time.Duration) (vkobj.Pricelist, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pricelist, err := self.GetPricelist(pricelistId)
+		if err != nil {
+			return pricelist, err
+		}
+
+		if pricelist.Status != vkobj.PricelistStatusProcessing {
+			return pricelist, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return pricelist, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
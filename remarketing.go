@@ -0,0 +1,165 @@
+package vkads
+
+import (
+	"context"
+	"errors"
+	"github.com/sintanial/vkads/vkobj"
+	"strconv"
+	"time"
+)
+
+func (self *Api) GetSegments(options ...RequestOptions) Iterator[[]vkobj.Segment] {
+	return createApiIterator[[]vkobj.Segment](self, "/api/v2/remarketing/segments.json", options...)
+}
+
+func (self *Api) GetSegment(segmentId int, options ...RequestOptions) (response vkobj.Segment, err error) {
+	err = self.getRequestUnmarshal("/api/v2/remarketing/segments/"+strconv.Itoa(segmentId)+".json", &response, options...)
+	return
+}
+
+type SegmentRequest struct {
+	Name          string                  `json:"name,omitempty"`
+	PassCondition int                     `json:"pass_condition,omitempty"`
+	Relations     []vkobj.SegmentRelation `json:"relations,omitempty"`
+}
+
+type CreateSegmentResponse struct {
+	Id int `json:"id"`
+}
+
+func (self *Api) CreateSegment(request SegmentRequest) (response CreateSegmentResponse, err error) {
+	err = self.postJsonRequestUnmarshal("/api/v2/remarketing/segments.json", &response, request)
+	return
+}
+
+func (self *Api) UpdateSegment(segmentId int, request SegmentRequest) (response vkobj.Segment, err error) {
+	err = self.postJsonRequestUnmarshal("/api/v2/remarketing/segments/"+strconv.Itoa(segmentId)+".json", &response, request)
+	return
+}
+
+func (self *Api) DeleteSegment(segmentId int) error {
+	return self.deleteRequest("/api/v2/remarketing/segments/" + strconv.Itoa(segmentId) + ".json")
+}
+
+// CreateLookalikeRequest describes a similar audience built from exactly one
+// of SourceSegmentId and SourceUsersListId. AudienceSize is the wanted number
+// of users, the api picks its own when zero.
+type CreateLookalikeRequest struct {
+	Name              string `json:"name"`
+	SourceSegmentId   int    `json:"source_segment_id,omitempty"`
+	SourceUsersListId int    `json:"source_users_list_id,omitempty"`
+	AudienceSize      int    `json:"audience_size,omitempty"`
+	Regions           []int  `json:"regions,omitempty"`
+}
+
+func (self *Api) CreateLookalike(request CreateLookalikeRequest) (response CreateSegmentResponse, err error) {
+	if (request.SourceSegmentId == 0) == (request.SourceUsersListId == 0) {
+		return response, errors.New("exactly one of source_segment_id and source_users_list_id must be set")
+	}
+
+	err = self.postJsonRequestUnmarshal("/api/v2/remarketing/segments/lookalike.json", &response, request)
+	return
+}
+
+// WaitSegment polls the segment every interval until it leaves the building
+// status or ctx is done.
+func (self *Api) WaitSegment(ctx context.Context, segmentId int, interval time.Duration) (vkobj.Segment, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		segment, err := self.GetSegment(segmentId)
+		if err != nil {
+			return segment, err
+		}
+
+		if segment.Status != vkobj.SegmentStatusBuilding {
+			return segment, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return segment, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (self *Api) GetSegmentRelations(segmentId int, options ...RequestOptions) Iterator[[]vkobj.SegmentRelation] {
+	return createApiIterator[[]vkobj.SegmentRelation](self, "/api/v2/remarketing/segments/"+strconv.Itoa(segmentId)+"/relations.json", options...)
+}
+
+type AddSegmentRelationsResponse struct {
+	Items []vkobj.SegmentRelation `json:"items"`
+}
+
+func (self *Api) AddSegmentRelations(segmentId int, relations []vkobj.SegmentRelation) (response AddSegmentRelationsResponse, err error) {
+	err = self.postJsonRequestUnmarshal("/api/v2/remarketing/segments/"+strconv.Itoa(segmentId)+"/relations.json", &response, relations)
+	return
+}
+
+func (self *Api) DeleteSegmentRelation(segmentId int, relationId int) error {
+	return self.deleteRequest("/api/v2/remarketing/segments/" + strconv.Itoa(segmentId) + "/relations/" + strconv.Itoa(relationId) + ".json")
+}
+
+func (self *Api) GetRemarketingCounters(options ...RequestOptions) Iterator[[]vkobj.RemarketingCounter] {
+	return createApiIterator[[]vkobj.RemarketingCounter](self, "/api/v2/remarketing/counters.json", options...)
+}
+
+type CreateRemarketingCounterRequest struct {
+	CounterId int    `json:"counter_id"`
+	Name      string `json:"name,omitempty"`
+}
+
+// CreateRemarketingCounter attaches an existing Top@Mail.ru counter to the
+// account.
+func (self *Api) CreateRemarketingCounter(request CreateRemarketingCounterRequest) (response vkobj.RemarketingCounter, err error) {
+	err = self.postJsonRequestUnmarshal("/api/v2/remarketing/counters.json", &response, request)
+	return
+}
+
+func (self *Api) DeleteRemarketingCounter(counterId int) error {
+	return self.deleteRequest("/api/v2/remarketing/counters/" + strconv.Itoa(counterId) + ".json")
+}
+
+func (self *Api) GetCounterGoals(counterId int, options ...RequestOptions) Iterator[[]vkobj.CounterGoal] {
+	return createApiIterator[[]vkobj.CounterGoal](self, "/api/v2/remarketing/counters/"+strconv.Itoa(counterId)+"/goals.json", options...)
+}
+
+// CreateCounterGoalSegment creates a segment of users who reached the goal in
+// the last days.
+func (self *Api) CreateCounterGoalSegment(name string, goal vkobj.CounterGoal, days int) (CreateSegmentResponse, error) {
+	return self.CreateSegment(SegmentRequest{
+		Name:          name,
+		PassCondition: 1,
+		Relations:     []vkobj.SegmentRelation{goal.SegmentRelation(days, 0)},
+	})
+}
+
+func (self *Api) GetContextPhrasesLists(options ...RequestOptions) Iterator[[]vkobj.ContextPhrases] {
+	return createApiIterator[[]vkobj.ContextPhrases](self, "/api/v2/remarketing/context_phrases.json", options...)
+}
+
+func (self *Api) GetContextPhrasesList(contextPhrasesId int, options ...RequestOptions) (response vkobj.ContextPhrases, err error) {
+	err = self.getRequestUnmarshal("/api/v2/remarketing/context_phrases/"+strconv.Itoa(contextPhrasesId)+".json", &response, options...)
+	return
+}
+
+func (self *Api) CreateContextPhrasesList(phrases vkobj.ContextPhrases) (response vkobj.ContextPhrases, err error) {
+	err = self.postJsonRequestUnmarshal("/api/v2/remarketing/context_phrases.json", &response, phrases)
+	return
+}
+
+func (self *Api) UpdateContextPhrasesList(contextPhrasesId int, phrases vkobj.ContextPhrases) (response vkobj.ContextPhrases, err error) {
+	err = self.postJsonRequestUnmarshal("/api/v2/remarketing/context_phrases/"+strconv.Itoa(contextPhrasesId)+".json", &response, phrases)
+	return
+}
+
+func (self *Api) DeleteContextPhrasesList(contextPhrasesId int) error {
+	return self.deleteRequest("/api/v2/remarketing/context_phrases/" + strconv.Itoa(contextPhrasesId) + ".json")
+}
+
+// GetVkGroups lists the VK communities which can be used in segment relations.
+func (self *Api) GetVkGroups(options ...RequestOptions) Iterator[[]vkobj.VkGroup] {
+	return createApiIterator[[]vkobj.VkGroup](self, "/api/v2/remarketing/vk_groups.json", options...)
+}
@@ -0,0 +1,310 @@
+package vkads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/sintanial/vkads/vkobj"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+type EntityKind string
+
+const EntityKindAdPlan EntityKind = "ad_plan"
+const EntityKindAdGroup EntityKind = "ad_group"
+const EntityKindBanner EntityKind = "banner"
+
+type ChangeType string
+
+const ChangeTypeCreated ChangeType = "created"
+const ChangeTypeUpdated ChangeType = "updated"
+
+// FieldChange is a changed field of an entity, Field is a dot separated json
+// path. Old is nil for added fields and New is nil for removed ones.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type ChangeEvent struct {
+	Kind    EntityKind      `json:"kind"`
+	Id      int             `json:"id"`
+	Type    ChangeType      `json:"type"`
+	Changes []FieldChange   `json:"changes,omitempty"`
+	Entity  json.RawMessage `json:"entity"`
+	Time    time.Time       `json:"time"`
+}
+
+// Snapshot is the last known state of the entities of a kind. Since is the
+// api time the last complete poll started at, next polls only ask for
+// entities updated after it.
+type Snapshot struct {
+	Since    *vkobj.DateTime                `json:"since,omitempty"`
+	Entities map[int]map[string]interface{} `json:"entities"`
+}
+
+// ChangeStore persists the snapshots and the emitted events of a tracker.
+type ChangeStore interface {
+	LoadSnapshot(kind EntityKind) (Snapshot, error)
+	SaveSnapshot(kind EntityKind, snapshot Snapshot) error
+	AppendEvents(events []ChangeEvent) error
+}
+
+type MemoryChangeStore struct {
+	mu        sync.Mutex
+	snapshots map[EntityKind]Snapshot
+	Events    []ChangeEvent
+}
+
+func NewMemoryChangeStore() *MemoryChangeStore {
+	return &MemoryChangeStore{snapshots: make(map[EntityKind]Snapshot)}
+}
+
+func (s *MemoryChangeStore) LoadSnapshot(kind EntityKind) (Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshots[kind], nil
+}
+
+func (s *MemoryChangeStore) SaveSnapshot(kind EntityKind, snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots[kind] = snapshot
+	return nil
+}
+
+func (s *MemoryChangeStore) AppendEvents(events []ChangeEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Events = append(s.Events, events...)
+	return nil
+}
+
+type trackedEntity struct {
+	uri    string
+	fields []string
+}
+
+// ChangeTracker detects changes of ad plans, ad groups and banners made by
+// anyone, by polling entities updated since the previous snapshot and diffing
+// them field by field. The first poll of a kind records the snapshot and
+// reports every entity as created.
+type ChangeTracker struct {
+	Kinds []EntityKind
+	// RequestInterval is the minimal delay between page requests of a poll.
+	RequestInterval time.Duration
+	// MaxInterval caps the doubled delay of rate limited polls in Run, zero
+	// means 16 times the interval.
+	MaxInterval time.Duration
+
+	api   *Api
+	store ChangeStore
+	now   func() time.Time
+}
+
+func (self *Api) NewChangeTracker(store ChangeStore) *ChangeTracker {
+	return &ChangeTracker{
+		Kinds: []EntityKind{EntityKindAdPlan, EntityKindAdGroup, EntityKindBanner},
+		api:   self,
+		store: store,
+		now:   time.Now,
+	}
+}
+
+func (t *ChangeTracker) entity(kind EntityKind) trackedEntity {
+	switch kind {
+	case EntityKindAdPlan:
+		return trackedEntity{uri: "/api/v2/ad_plans.json", fields: AdPlanAllFieldsOption}
+	case EntityKindAdGroup:
+		return trackedEntity{uri: "/api/v2/ad_groups.json", fields: AdGroupAllFieldsOption}
+	default:
+		return trackedEntity{uri: "/api/v2/banners.json", fields: BannerAllFieldsOption}
+	}
+}
+
+// Poll fetches the changed entities of every kind and returns the events.
+// The events and the new snapshots are stored only when every kind is
+// fetched, so a failed poll is retried from the same snapshots.
+func (t *ChangeTracker) Poll() ([]ChangeEvent, error) {
+	var result []ChangeEvent
+	snapshots := make(map[EntityKind]Snapshot)
+	for _, kind := range t.Kinds {
+		snapshot, events, err := t.poll(kind)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots[kind] = snapshot
+		result = append(result, events...)
+	}
+
+	// events go first, a failed save reports them again instead of losing them
+	if len(result) > 0 {
+		if err := t.store.AppendEvents(result); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, kind := range t.Kinds {
+		if err := t.store.SaveSnapshot(kind, snapshots[kind]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (t *ChangeTracker) poll(kind EntityKind) (Snapshot, []ChangeEvent, error) {
+	snapshot, err := t.store.LoadSnapshot(kind)
+	if err != nil {
+		return snapshot, nil, err
+	}
+
+	// work on a copy, the stored snapshot must stay intact if a page fails
+	entities := make(map[int]map[string]interface{}, len(snapshot.Entities))
+	for id, current := range snapshot.Entities {
+		entities[id] = current
+	}
+	snapshot.Entities = entities
+
+	// entities updated during the poll leave the listing, they are reported
+	// by the next poll which starts at until. The listing is paged by id, so
+	// leaving entities don't shift the pages.
+	now := t.now()
+	until := t.api.DateTime(now.Truncate(time.Second))
+
+	entity := t.entity(kind)
+	option := NewRequestOptions().SetFields(entity.fields).SetLimit(250)
+	option.Set("_updated__lt", until.String())
+	if snapshot.Since != nil {
+		// entities updated within the same second as Since may have been
+		// missed, look one second back and rely on the diff to skip duplicates
+		option.Set("_updated__gt", snapshot.Since.Add(-time.Second).String())
+	}
+
+	it := createApiCursorIterator(t.api, entity.uri, rawEntityId, option)
+	it.RequestInterval = t.RequestInterval
+
+	var events []ChangeEvent
+	for it.HasNext() {
+		page, err := it.Next()
+		if err != nil {
+			return snapshot, nil, err
+		}
+
+		for _, raw := range page.Items {
+			var current map[string]interface{}
+			if err := json.Unmarshal(raw, &current); err != nil {
+				return snapshot, nil, err
+			}
+
+			id := rawEntityId(raw)
+			event := ChangeEvent{Kind: kind, Id: id, Entity: raw, Time: now}
+			previous, known := snapshot.Entities[id]
+			if !known {
+				event.Type = ChangeTypeCreated
+			} else {
+				event.Type = ChangeTypeUpdated
+				event.Changes = diffFields("", previous, current, nil)
+				if len(event.Changes) == 0 {
+					continue
+				}
+			}
+
+			snapshot.Entities[id] = current
+			events = append(events, event)
+		}
+	}
+
+	snapshot.Since = &until
+	return snapshot, events, nil
+}
+
+func rawEntityId(raw json.RawMessage) int {
+	var entity struct {
+		Id int `json:"id"`
+	}
+	json.Unmarshal(raw, &entity)
+	return entity.Id
+}
+
+// Run polls every interval until ctx is done and calls fn for every event.
+// Rate limited polls are retried with a doubled delay up to MaxInterval.
+func (t *ChangeTracker) Run(ctx context.Context, interval time.Duration, fn func(ChangeEvent)) error {
+	if interval <= 0 {
+		return errors.New("change tracker interval must be positive")
+	}
+
+	maxInterval := t.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = 16 * interval
+	}
+
+	delay := interval
+	for {
+		events, err := t.Poll()
+		if err != nil && !IsRateLimited(err) {
+			return err
+		}
+
+		if err != nil {
+			delay *= 2
+			if delay > maxInterval {
+				delay = maxInterval
+			}
+		} else {
+			delay = interval
+		}
+
+		for _, event := range events {
+			fn(event)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func diffFields(prefix string, old map[string]interface{}, new map[string]interface{}, changes []FieldChange) []FieldChange {
+	keys := make(map[string]bool)
+	for key := range old {
+		keys[key] = true
+	}
+	for key := range new {
+		keys[key] = true
+	}
+
+	var sorted []string
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		oldValue, newValue := old[key], new[key]
+		oldMap, oldIsMap := oldValue.(map[string]interface{})
+		newMap, newIsMap := newValue.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			changes = diffFields(path, oldMap, newMap, changes)
+			continue
+		}
+
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, FieldChange{Field: path, Old: oldValue, New: newValue})
+		}
+	}
+
+	return changes
+}
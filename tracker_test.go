@@ -0,0 +1,211 @@
+package vkads
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffFields(t *testing.T) {
+	tests := []struct {
+		name string
+		old  map[string]interface{}
+		new  map[string]interface{}
+		want []FieldChange
+	}{
+		{
+			name: "equal",
+			old:  map[string]interface{}{"name": "a", "ids": []interface{}{1.0, 2.0}},
+			new:  map[string]interface{}{"name": "a", "ids": []interface{}{1.0, 2.0}},
+		},
+		{
+			name: "changed, added and removed",
+			old:  map[string]interface{}{"name": "a", "status": "active"},
+			new:  map[string]interface{}{"name": "b", "budget": "100"},
+			want: []FieldChange{
+				{Field: "budget", New: "100"},
+				{Field: "name", Old: "a", New: "b"},
+				{Field: "status", Old: "active"},
+			},
+		},
+		{
+			name: "nested objects",
+			old:  map[string]interface{}{"targetings": map[string]interface{}{"age": map[string]interface{}{"age_list": []interface{}{18.0}}, "sex": "male"}},
+			new:  map[string]interface{}{"targetings": map[string]interface{}{"age": map[string]interface{}{"age_list": []interface{}{18.0, 19.0}}, "sex": "male"}},
+			want: []FieldChange{
+				{Field: "targetings.age.age_list", Old: []interface{}{18.0}, New: []interface{}{18.0, 19.0}},
+			},
+		},
+		{
+			name: "object replaced by a value",
+			old:  map[string]interface{}{"price": map[string]interface{}{"value": "1"}},
+			new:  map[string]interface{}{"price": nil},
+			want: []FieldChange{
+				{Field: "price", Old: map[string]interface{}{"value": "1"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := diffFields("", test.old, test.new, nil)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+type trackerClock struct {
+	now time.Time
+}
+
+func (c *trackerClock) Now() time.Time {
+	return c.now
+}
+
+func newTestTracker(entities *fakeEntities, clock *trackerClock) (*ChangeTracker, *MemoryChangeStore) {
+	store := NewMemoryChangeStore()
+	tracker := entities.api().NewChangeTracker(store)
+	tracker.Kinds = []EntityKind{EntityKindAdPlan}
+	tracker.now = clock.Now
+
+	return tracker, store
+}
+
+func eventIds(events []ChangeEvent) []int {
+	var ids []int
+	for _, event := range events {
+		ids = append(ids, event.Id)
+	}
+	return ids
+}
+
+func TestChangeTrackerEditDuringPoll(t *testing.T) {
+	entities := newFakeEntities()
+	entities.maxPage = 2
+	for id := 1; id <= 5; id++ {
+		entities.items[id] = map[string]interface{}{"id": id, "name": "v1", "updated": "2023-05-01 09:00:00"}
+	}
+
+	clock := &trackerClock{now: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)}
+	tracker, store := newTestTracker(entities, clock)
+
+	events, err := tracker.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := eventIds(events); !reflect.DeepEqual(ids, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("first poll reported %v", ids)
+	}
+	for _, event := range events {
+		if event.Type != ChangeTypeCreated {
+			t.Fatalf("first poll reported %+v", event)
+		}
+	}
+
+	for id := 1; id <= 4; id++ {
+		entities.items[id]["name"] = "v2"
+		entities.items[id]["updated"] = "2023-05-01 10:30:00"
+	}
+
+	// entity 1 is edited again right after the first page of the second poll
+	clock.now = time.Date(2023, 5, 1, 11, 0, 0, 0, time.UTC)
+	entities.queries = nil
+	edited := false
+	entities.afterRequest = func(n int) {
+		if !edited && entities.queries[n-1].Get("_sorting") != "-id" {
+			edited = true
+			entities.items[1]["name"] = "v3"
+			entities.items[1]["updated"] = "2023-05-01 11:00:05"
+		}
+	}
+
+	events, err = tracker.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := eventIds(events); !reflect.DeepEqual(ids, []int{1, 2, 3, 4}) {
+		t.Fatalf("second poll reported %v", ids)
+	}
+	for _, event := range events {
+		want := []FieldChange{{Field: "name", Old: "v1", New: "v2"}, {Field: "updated", Old: "2023-05-01 09:00:00", New: "2023-05-01 10:30:00"}}
+		if event.Type != ChangeTypeUpdated || !reflect.DeepEqual(event.Changes, want) {
+			t.Fatalf("second poll reported %+v", event)
+		}
+	}
+
+	query := entities.queries[0]
+	if query.Get("_updated__gt") != "2023-05-01 09:59:59" || query.Get("_updated__lt") != "2023-05-01 11:00:00" {
+		t.Fatalf("unexpected range in %v", query)
+	}
+
+	clock.now = time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	entities.afterRequest = nil
+
+	events, err = tracker.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Id != 1 || events[0].Changes[0] != (FieldChange{Field: "name", Old: "v2", New: "v3"}) {
+		t.Fatalf("third poll reported %+v", events)
+	}
+
+	if len(store.Events) != 10 {
+		t.Fatalf("stored %d events", len(store.Events))
+	}
+}
+
+func TestChangeTrackerFailedPoll(t *testing.T) {
+	entities := newFakeEntities(1, 2)
+	clock := &trackerClock{now: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)}
+
+	failing := true
+	store := NewMemoryChangeStore()
+	api := NewWithHttpClient(Token{}, &http.Client{Transport: roundTripFunc(func(request *http.Request) (*http.Response, error) {
+		if failing && strings.Contains(request.URL.Path, "banners") {
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Status:     "500 Internal Server Error",
+				Body:       ioutil.NopCloser(strings.NewReader("oops")),
+				Request:    request,
+			}, nil
+		}
+		return entities.RoundTrip(request)
+	})})
+	api.Debug(false)
+
+	tracker := api.NewChangeTracker(store)
+	tracker.Kinds = []EntityKind{EntityKindAdPlan, EntityKindBanner}
+	tracker.now = clock.Now
+
+	events, err := tracker.Poll()
+	if err == nil || events != nil {
+		t.Fatalf("got %v and %v", events, err)
+	}
+
+	snapshot, _ := store.LoadSnapshot(EntityKindAdPlan)
+	if len(store.Events) != 0 || snapshot.Since != nil || len(snapshot.Entities) != 0 {
+		t.Fatalf("failed poll stored %+v and %+v", store.Events, snapshot)
+	}
+
+	failing = false
+	events, err = tracker.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 4 || len(store.Events) != 4 {
+		t.Fatalf("got %+v", events)
+	}
+}
+
+func TestChangeTrackerRunRejectsZeroInterval(t *testing.T) {
+	tracker := New(Token{}).NewChangeTracker(NewMemoryChangeStore())
+	if err := tracker.Run(context.Background(), 0, func(ChangeEvent) {}); err == nil {
+		t.Fatal("Run accepted a zero interval")
+	}
+}
@@ -0,0 +1,60 @@
+package vkads
+
+import (
+	"github.com/sintanial/vkads/vkobj"
+	"strconv"
+)
+
+// CreateUrl registers the url, the response carries the url id to be used in
+// banners and the object type detected by the api (site, app, VK post, ...).
+// Registering an already known url returns the existing record.
+func (self *Api) CreateUrl(url string) (response vkobj.Urls, err error) {
+	err = self.postJsonRequestUnmarshal("/api/v2/urls.json", &response, map[string]string{"url": url})
+	return
+}
+
+func (self *Api) GetUrl(urlId int) (response vkobj.Urls, err error) {
+	err = self.getRequestUnmarshal("/api/v2/urls/"+strconv.Itoa(urlId)+".json", &response)
+	return
+}
+
+// ResolveBannerUrls registers plain urls by their banner role and returns them
+// in the form expected by vkobj.Banner.Urls.
+func (self *Api) ResolveBannerUrls(urls map[string]string) (map[string]vkobj.Urls, error) {
+	registered := make(map[string]vkobj.Urls)
+	result := make(map[string]vkobj.Urls)
+	for role, url := range urls {
+		u, ok := registered[url]
+		if !ok {
+			var err error
+			u, err = self.CreateUrl(url)
+			if err != nil {
+				return nil, err
+			}
+			registered[url] = u
+		}
+
+		result[role] = vkobj.Urls{Id: u.Id}
+	}
+
+	return result, nil
+}
+
+// SetBannerUrls registers the urls and sets them on the banner, keeping the
+// banner urls of other roles.
+func (self *Api) SetBannerUrls(banner *vkobj.Banner, urls map[string]string) error {
+	resolved, err := self.ResolveBannerUrls(urls)
+	if err != nil {
+		return err
+	}
+
+	if banner.Urls == nil {
+		banner.Urls = make(map[string]vkobj.Urls)
+	}
+
+	for role, u := range resolved {
+		banner.Urls[role] = u
+	}
+
+	return nil
+}
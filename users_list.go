@@ -0,0 +1,223 @@
+package vkads
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/sintanial/vkads/vkobj"
+	"io"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type UsersListHash string
+
+const UsersListHashNone UsersListHash = ""
+const UsersListHashMd5 UsersListHash = "md5"
+const UsersListHashSha256 UsersListHash = "sha256"
+
+type UsersListOptions struct {
+	Name string
+	Type vkobj.UsersListType
+	Hash UsersListHash
+	// RuPhones rewrites russian phones written with a leading 8 to the +7
+	// form, enable it only for lists of russian numbers.
+	RuPhones bool
+}
+
+type UploadUsersListResponse struct {
+	vkobj.UsersList
+	// Skipped is the number of input lines dropped by the local normalization.
+	Skipped int `json:"-"`
+}
+
+// UploadUsersList reads one identifier per line from r, normalizes it for the
+// list type, optionally hashes it and uploads the result as a new users list.
+func (self *Api) UploadUsersList(r io.Reader, opt UsersListOptions) (response UploadUsersListResponse, err error) {
+	entries, skipped, err := PrepareUsersList(r, opt)
+	if err != nil {
+		return response, err
+	}
+
+	if entries.Len() == 0 {
+		return response, errors.New("users list has no valid entries")
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	data, err := json.Marshal(map[string]string{
+		"name": opt.Name,
+		"type": string(opt.Type),
+	})
+	if err != nil {
+		return response, err
+	}
+
+	if err = w.WriteField("data", string(data)); err != nil {
+		return response, err
+	}
+
+	filePart, err := w.CreateFormFile("file", "users.txt")
+	if err != nil {
+		return response, err
+	}
+
+	if _, err = io.Copy(filePart, entries); err != nil {
+		return response, err
+	}
+
+	if err = w.Close(); err != nil {
+		return response, err
+	}
+
+	err = self.postMultipartRequestUnmarshal("/api/v2/remarketing/users_lists.json", w.FormDataContentType(), &buf, &response.UsersList)
+	response.Skipped = skipped
+	return
+}
+
+func (self *Api) GetUsersLists(options ...RequestOptions) Iterator[[]vkobj.UsersList] {
+	return createApiIterator[[]vkobj.UsersList](self, "/api/v2/remarketing/users_lists.json", options...)
+}
+
+func (self *Api) GetUsersList(usersListId int, options ...RequestOptions) (response vkobj.UsersList, err error) {
+	err = self.getRequestUnmarshal("/api/v2/remarketing/users_lists/"+strconv.Itoa(usersListId)+".json", &response, options...)
+	return
+}
+
+func (self *Api) DeleteUsersList(usersListId int) error {
+	return self.deleteRequest("/api/v2/remarketing/users_lists/" + strconv.Itoa(usersListId) + ".json")
+}
+
+// WaitUsersList polls the users list every interval until it leaves the
+// processing status or ctx is done.
+func (self *Api) WaitUsersList(ctx context.Context, usersListId int, interval time.Duration) (vkobj.UsersList, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		list, err := self.GetUsersList(usersListId)
+		if err != nil {
+			return list, err
+		}
+
+		if list.Status != vkobj.UsersListStatusProcessing {
+			return list, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return list, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// PrepareUsersList normalizes and hashes the lines of r for opt.Type and
+// opt.Hash. Lines which can't be normalized for the list type are skipped and
+// counted.
+func PrepareUsersList(r io.Reader, opt UsersListOptions) (*bytes.Buffer, int, error) {
+	var buf bytes.Buffer
+	skipped := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		entry, ok := NormalizeUsersListEntry(opt.Type, line)
+		if !ok {
+			skipped++
+			continue
+		}
+
+		if opt.RuPhones && opt.Type == vkobj.UsersListTypePhone {
+			entry = NormalizeRuPhone(entry)
+		}
+
+		buf.WriteString(HashUsersListEntry(opt.Hash, entry))
+		buf.WriteByte('\n')
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, skipped, err
+	}
+
+	return &buf, skipped, nil
+}
+
+func NormalizeUsersListEntry(tp vkobj.UsersListType, entry string) (string, bool) {
+	entry = strings.TrimSpace(entry)
+
+	switch tp {
+	case vkobj.UsersListTypeEmail:
+		entry = strings.ToLower(entry)
+		at := strings.Index(entry, "@")
+		if at <= 0 || at != strings.LastIndex(entry, "@") || at == len(entry)-1 {
+			return "", false
+		}
+		return entry, true
+	case vkobj.UsersListTypePhone:
+		digits := onlyDigits(entry)
+		if len(digits) < 10 || len(digits) > 15 {
+			return "", false
+		}
+		return digits, true
+	case vkobj.UsersListTypeVk:
+		entry = strings.TrimPrefix(strings.ToLower(entry), "id")
+		if entry == "" || onlyDigits(entry) != entry {
+			return "", false
+		}
+		return entry, true
+	case vkobj.UsersListTypeGaid, vkobj.UsersListTypeIdfa:
+		entry = strings.ToLower(entry)
+		if len(strings.ReplaceAll(entry, "-", "")) != 32 {
+			return "", false
+		}
+		return entry, true
+	}
+
+	return entry, entry != ""
+}
+
+// NormalizeRuPhone rewrites a normalized russian phone with the domestic
+// leading 8 to the international 7. It must not be applied to other countries,
+// e.g. japanese numbers start with 81.
+func NormalizeRuPhone(digits string) string {
+	if len(digits) == 11 && digits[0] == '8' {
+		return "7" + digits[1:]
+	}
+	return digits
+}
+
+func HashUsersListEntry(hash UsersListHash, entry string) string {
+	switch hash {
+	case UsersListHashMd5:
+		sum := md5.Sum([]byte(entry))
+		return hex.EncodeToString(sum[:])
+	case UsersListHashSha256:
+		sum := sha256.Sum256([]byte(entry))
+		return hex.EncodeToString(sum[:])
+	}
+
+	return entry
+}
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
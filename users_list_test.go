@@ -0,0 +1,106 @@
+package vkads
+
+import (
+	"github.com/sintanial/vkads/vkobj"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeUsersListEntry(t *testing.T) {
+	tests := []struct {
+		tp    vkobj.UsersListType
+		entry string
+		want  string
+		ok    bool
+	}{
+		{tp: vkobj.UsersListTypeEmail, entry: " User@Example.COM ", want: "user@example.com", ok: true},
+		{tp: vkobj.UsersListTypeEmail, entry: "@example.com"},
+		{tp: vkobj.UsersListTypeEmail, entry: "user@"},
+		{tp: vkobj.UsersListTypeEmail, entry: "a@b@c"},
+		{tp: vkobj.UsersListTypePhone, entry: "+7 (912) 345-67-89", want: "79123456789", ok: true},
+		{tp: vkobj.UsersListTypePhone, entry: "8 912 345 67 89", want: "89123456789", ok: true},
+		{tp: vkobj.UsersListTypePhone, entry: "+81 3 1234 5678", want: "81312345678", ok: true},
+		{tp: vkobj.UsersListTypePhone, entry: "12345"},
+		{tp: vkobj.UsersListTypePhone, entry: "1234567890123456"},
+		{tp: vkobj.UsersListTypeVk, entry: "id12345", want: "12345", ok: true},
+		{tp: vkobj.UsersListTypeVk, entry: "12345", want: "12345", ok: true},
+		{tp: vkobj.UsersListTypeVk, entry: "durov"},
+		{tp: vkobj.UsersListTypeGaid, entry: "38400000-8CF0-11BD-B23E-10B96E40000D", want: "38400000-8cf0-11bd-b23e-10b96e40000d", ok: true},
+		{tp: vkobj.UsersListTypeIdfa, entry: "not-an-idfa"},
+	}
+
+	for _, test := range tests {
+		got, ok := NormalizeUsersListEntry(test.tp, test.entry)
+		if ok != test.ok || got != test.want {
+			t.Errorf("%s %q: got %q %v, want %q %v", test.tp, test.entry, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestNormalizeRuPhone(t *testing.T) {
+	tests := map[string]string{
+		"89123456789":  "79123456789",
+		"79123456789":  "79123456789",
+		"8912345678":   "8912345678",
+		"812345678901": "812345678901",
+	}
+
+	for in, want := range tests {
+		if got := NormalizeRuPhone(in); got != want {
+			t.Errorf("NormalizeRuPhone(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestHashUsersListEntry(t *testing.T) {
+	tests := []struct {
+		hash UsersListHash
+		want string
+	}{
+		{hash: UsersListHashNone, want: "user@example.com"},
+		{hash: UsersListHashMd5, want: "b58996c504c5638798eb6b511e6f49af"},
+		{hash: UsersListHashSha256, want: "b4c9a289323b21a01c3e940f150eb9b8c542587f1abfd8f0e1cc1ffc5e475514"},
+	}
+
+	for _, test := range tests {
+		if got := HashUsersListEntry(test.hash, "user@example.com"); got != test.want {
+			t.Errorf("%q hash is %s, want %s", test.hash, got, test.want)
+		}
+	}
+}
+
+func TestPrepareUsersList(t *testing.T) {
+	input := "+7 912 345-67-89\n\n8 912 345 67 89\n+81 3 1234 5678\n123\n"
+
+	tests := []struct {
+		name    string
+		opt     UsersListOptions
+		want    string
+		skipped int
+	}{
+		{
+			name:    "international",
+			opt:     UsersListOptions{Type: vkobj.UsersListTypePhone},
+			want:    "79123456789\n89123456789\n81312345678\n",
+			skipped: 1,
+		},
+		{
+			name:    "russian",
+			opt:     UsersListOptions{Type: vkobj.UsersListTypePhone, RuPhones: true},
+			want:    "79123456789\n79123456789\n71312345678\n",
+			skipped: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf, skipped, err := PrepareUsersList(strings.NewReader(input), test.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != test.want || skipped != test.skipped {
+				t.Fatalf("got %q with %d skipped, want %q with %d skipped", buf.String(), skipped, test.want, test.skipped)
+			}
+		})
+	}
+}
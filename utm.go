@@ -0,0 +1,231 @@
+package vkads
+
+import (
+	"fmt"
+	"github.com/sintanial/vkads/vkobj"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// UtmMacros are the macros substituted by VK Ads in ad group utm templates.
+var UtmMacros = map[string]string{
+	"advertiser_id":      "advertiser account id",
+	"ad_plan_id":         "ad plan id",
+	"ad_plan_name":       "ad plan name",
+	"campaign_id":        "ad group id",
+	"campaign_name":      "ad group name",
+	"banner_id":          "banner id",
+	"geo":                "region id of the user",
+	"gender":             "gender of the user",
+	"age":                "age of the user",
+	"random":             "random number",
+	"impression_weekday": "weekday of the impression",
+	"impression_hour":    "hour of the impression",
+	"user_timezone":      "timezone of the user",
+	"search_phrase":      "search phrase of the user",
+	"device_type":        "device type of the user",
+}
+
+var utmMacroRe = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// Utm builds the query string stored in vkobj.AdGroup.Utm. Values may contain
+// macros like {{campaign_id}}, they are kept unescaped.
+type Utm struct {
+	keys   []string
+	values map[string]string
+}
+
+func NewUtm() *Utm {
+	return &Utm{values: make(map[string]string)}
+}
+
+// ParseUtm parses an utm query string, e.g. the current vkobj.AdGroup.Utm.
+func ParseUtm(s string) (*Utm, error) {
+	u := NewUtm()
+	for _, pair := range strings.Split(strings.TrimPrefix(s, "?"), "&") {
+		if pair == "" {
+			continue
+		}
+
+		key, value := pair, ""
+		if i := strings.Index(pair, "="); i >= 0 {
+			key, value = pair[:i], pair[i+1:]
+		}
+
+		var err error
+		if key, err = url.QueryUnescape(key); err != nil {
+			return nil, err
+		}
+		if value, err = unescapeUtmValue(value); err != nil {
+			return nil, err
+		}
+
+		u.Set(key, value)
+	}
+
+	return u, nil
+}
+
+func (u *Utm) Set(key string, value string) *Utm {
+	if _, ok := u.values[key]; !ok {
+		u.keys = append(u.keys, key)
+	}
+	u.values[key] = value
+	return u
+}
+
+func (u *Utm) Get(key string) string {
+	return u.values[key]
+}
+
+func (u *Utm) Source(value string) *Utm   { return u.Set("utm_source", value) }
+func (u *Utm) Medium(value string) *Utm   { return u.Set("utm_medium", value) }
+func (u *Utm) Campaign(value string) *Utm { return u.Set("utm_campaign", value) }
+func (u *Utm) Content(value string) *Utm  { return u.Set("utm_content", value) }
+func (u *Utm) Term(value string) *Utm     { return u.Set("utm_term", value) }
+
+func (u *Utm) String() string {
+	var pairs []string
+	for _, key := range u.keys {
+		pairs = append(pairs, url.QueryEscape(key)+"="+escapeUtmValue(u.values[key]))
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// Validate reports unknown macros and unbalanced macro braces. Values are
+// checked before escaping, which would hide stray braces.
+func (u *Utm) Validate() error {
+	for _, key := range u.keys {
+		if err := ValidateUtm(key + "=" + u.values[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Apply validates the template and enables it on the ad group.
+func (u *Utm) Apply(group *vkobj.AdGroup) error {
+	if err := u.Validate(); err != nil {
+		return err
+	}
+
+	s := u.String()
+	group.EnableUtm = true
+	group.Utm = &s
+	return nil
+}
+
+func ValidateUtm(s string) error {
+	rest := utmMacroRe.ReplaceAllString(s, "")
+	if strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+		return fmt.Errorf("utm %q has unbalanced macro braces", s)
+	}
+
+	for _, match := range utmMacroRe.FindAllStringSubmatch(s, -1) {
+		if _, ok := UtmMacros[match[1]]; !ok {
+			return fmt.Errorf("utm %q uses unknown macro {{%s}}", s, match[1])
+		}
+	}
+
+	return nil
+}
+
+// UtmPreview holds the entities macros are expanded for. Macros known only at
+// impression time (geo, age, ...) are taken from Values and kept as is when
+// missing there.
+type UtmPreview struct {
+	AdvertiserId int
+	AdPlan       vkobj.AdPlan
+	AdGroup      vkobj.AdGroup
+	Banner       vkobj.Banner
+	Values       map[string]string
+}
+
+func (p UtmPreview) Expand(s string) (string, error) {
+	if err := ValidateUtm(s); err != nil {
+		return "", err
+	}
+
+	known := map[string]string{
+		"advertiser_id": strconv.Itoa(p.AdvertiserId),
+		"ad_plan_id":    strconv.Itoa(p.AdPlan.Id),
+		"ad_plan_name":  p.AdPlan.Name,
+		"campaign_id":   strconv.Itoa(p.AdGroup.Id),
+		"campaign_name": p.AdGroup.Name,
+		"banner_id":     strconv.Itoa(p.Banner.Id),
+	}
+
+	return utmMacroRe.ReplaceAllStringFunc(s, func(macro string) string {
+		name := utmMacroRe.FindStringSubmatch(macro)[1]
+		if value, ok := p.Values[name]; ok {
+			return url.QueryEscape(value)
+		}
+		if value, ok := known[name]; ok && value != "" && value != "0" {
+			return url.QueryEscape(value)
+		}
+		return macro
+	}), nil
+}
+
+// Url returns link with the expanded utm template of the ad group appended.
+func (p UtmPreview) Url(link string) (string, error) {
+	if !p.AdGroup.EnableUtm || p.AdGroup.Utm == nil || *p.AdGroup.Utm == "" {
+		return link, nil
+	}
+
+	utm, err := p.Expand(*p.AdGroup.Utm)
+	if err != nil {
+		return "", err
+	}
+
+	fragment := ""
+	if i := strings.Index(link, "#"); i >= 0 {
+		link, fragment = link[:i], link[i:]
+	}
+
+	sep := "?"
+	if strings.Contains(link, "?") {
+		sep = "&"
+	}
+
+	return link + sep + utm + fragment, nil
+}
+
+func escapeUtmValue(value string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range utmMacroRe.FindAllStringIndex(value, -1) {
+		b.WriteString(url.QueryEscape(value[last:loc[0]]))
+		b.WriteString(value[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(url.QueryEscape(value[last:]))
+
+	return b.String()
+}
+
+func unescapeUtmValue(value string) (string, error) {
+	var b strings.Builder
+	last := 0
+	for _, loc := range utmMacroRe.FindAllStringIndex(value, -1) {
+		part, err := url.QueryUnescape(value[last:loc[0]])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(part)
+		b.WriteString(value[loc[0]:loc[1]])
+		last = loc[1]
+	}
+
+	part, err := url.QueryUnescape(value[last:])
+	if err != nil {
+		return "", err
+	}
+	b.WriteString(part)
+
+	return b.String(), nil
+}
@@ -0,0 +1,144 @@
+package vkads
+
+import (
+	"github.com/sintanial/vkads/vkobj"
+	"testing"
+)
+
+func TestUtmString(t *testing.T) {
+	tests := []struct {
+		name string
+		utm  *Utm
+		want string
+	}{
+		{
+			name: "plain values are escaped",
+			utm:  NewUtm().Source("vk ads").Medium("cpc&more"),
+			want: "utm_source=vk+ads&utm_medium=cpc%26more",
+		},
+		{
+			name: "macros are kept",
+			utm:  NewUtm().Campaign("{{campaign_id}}").Content("b {{banner_id}}/x"),
+			want: "utm_campaign={{campaign_id}}&utm_content=b+{{banner_id}}%2Fx",
+		},
+		{
+			name: "set keeps the key order",
+			utm:  NewUtm().Source("a").Medium("b").Source("c"),
+			want: "utm_source=c&utm_medium=b",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.utm.String(); got != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseUtm(t *testing.T) {
+	tests := []string{
+		"utm_source=vk+ads&utm_medium=cpc%26more",
+		"utm_campaign={{campaign_id}}&utm_content=b+{{banner_id}}%2Fx",
+		"flag=&utm_term={{search_phrase}}",
+	}
+
+	for _, in := range tests {
+		u, err := ParseUtm("?" + in)
+		if err != nil {
+			t.Fatalf("parse %s: %v", in, err)
+		}
+		if got := u.String(); got != in {
+			t.Errorf("round trip of %s gave %s", in, got)
+		}
+	}
+
+	u, err := ParseUtm("utm_source=vk+ads&utm_content=b+{{banner_id}}%2Fx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.Get("utm_source"); got != "vk ads" {
+		t.Errorf("utm_source is %q", got)
+	}
+	if got := u.Get("utm_content"); got != "b {{banner_id}}/x" {
+		t.Errorf("utm_content is %q", got)
+	}
+
+	if _, err := ParseUtm("utm_source=%zz"); err == nil {
+		t.Error("invalid escape parsed")
+	}
+}
+
+func TestUtmValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		utm   *Utm
+		valid bool
+	}{
+		{name: "known macro", utm: NewUtm().Campaign("{{campaign_id}}"), valid: true},
+		{name: "spaces in macro", utm: NewUtm().Campaign("{{ campaign_id }}"), valid: true},
+		{name: "unknown macro", utm: NewUtm().Campaign("{{campaign}}")},
+		{name: "missing closing brace", utm: NewUtm().Campaign("{{campaign_id}")},
+		{name: "missing opening brace", utm: NewUtm().Campaign("{campaign_id}}")},
+		{name: "brace in key", utm: NewUtm().Set("{{x", "1")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.utm.Validate()
+			if test.valid && err != nil {
+				t.Fatal(err)
+			}
+			if !test.valid && err == nil {
+				t.Fatalf("%s passed validation", test.utm)
+			}
+
+			var group vkobj.AdGroup
+			if err := test.utm.Apply(&group); (err == nil) != test.valid || group.EnableUtm != test.valid {
+				t.Fatalf("Apply returned %v and set EnableUtm %v", err, group.EnableUtm)
+			}
+		})
+	}
+}
+
+func TestUtmPreview(t *testing.T) {
+	utm := "utm_campaign={{campaign_id}}&utm_content={{banner_id}}&geo={{geo}}&age={{age}}"
+	preview := UtmPreview{
+		AdGroup: vkobj.AdGroup{Id: 12, EnableUtm: true, Utm: &utm},
+		Values:  map[string]string{"geo": "Москва"},
+	}
+
+	tests := []struct {
+		link string
+		want string
+	}{
+		{
+			link: "https://example.com/",
+			want: "https://example.com/?utm_campaign=12&utm_content={{banner_id}}&geo=%D0%9C%D0%BE%D1%81%D0%BA%D0%B2%D0%B0&age={{age}}",
+		},
+		{
+			link: "https://example.com/?a=1#top",
+			want: "https://example.com/?a=1&utm_campaign=12&utm_content={{banner_id}}&geo=%D0%9C%D0%BE%D1%81%D0%BA%D0%B2%D0%B0&age={{age}}#top",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := preview.Url(test.link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("got %s, want %s", got, test.want)
+		}
+	}
+
+	preview.AdGroup.EnableUtm = false
+	if got, _ := preview.Url("https://example.com/"); got != "https://example.com/" {
+		t.Errorf("disabled utm gave %s", got)
+	}
+
+	if _, err := (UtmPreview{}).Expand("a={{nope}}"); err == nil {
+		t.Error("unknown macro expanded")
+	}
+}
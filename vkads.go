@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const host = "https://ads.vk.com"
@@ -43,6 +44,14 @@ func NewRequestOptions(values ...url.Values) RequestOptions {
 	return r
 }
 
+func (o RequestOptions) Clone() RequestOptions {
+	values := make(url.Values, len(o.Values))
+	for key, val := range o.Values {
+		values[key] = append([]string(nil), val...)
+	}
+	return RequestOptions{Values: values}
+}
+
 func (o RequestOptions) GetLimit() int {
 	limit, _ := strconv.Atoi(o.Get("limit"))
 	return limit
@@ -90,15 +99,40 @@ type Iterable[T any] struct {
 	Offset int `json:"offset"`
 }
 
+// Iterator pages through a list endpoint by offset. The first page is
+// requested at InitialOffset with InitialLimit items, next pages follow the
+// limit and offset echoed by the api.
 type Iterator[T any] struct {
 	InitialLimit  int
 	InitialOffset int
+	// MaxItems caps the number of items fetched starting from InitialOffset,
+	// zero means no cap.
+	MaxItems int
+
+	// Concurrency enables prefetching: once the first page reveals Count, up to
+	// Concurrency next pages are fetched in parallel while pages are still
+	// returned in order. Values below 2 keep sequential fetching.
+	Concurrency int
+	// RequestInterval is the minimal delay between starting page requests,
+	// use it to stay under the api rate limits.
+	RequestInterval time.Duration
 
 	lastResponse *Iterable[T]
 
+	prefetching    bool
+	prefetchOffset int
+	prefetchLimit  int
+	pending        []chan prefetchResult[T]
+	lastRequest    time.Time
+
 	next func(limit int, offset int) (*Iterable[T], error)
 }
 
+type prefetchResult[T any] struct {
+	response *Iterable[T]
+	err      error
+}
+
 func (self *Iterator[T]) HasNext() bool {
 	if self.lastResponse == nil {
 		return true
@@ -108,7 +142,7 @@ func (self *Iterator[T]) HasNext() bool {
 		return false
 	}
 
-	return self.lastResponse.Limit+self.lastResponse.Offset < self.lastResponse.Count
+	return self.nextOffset() < self.endOffset()
 }
 
 func (self *Iterator[T]) Next() (*Iterable[T], error) {
@@ -116,18 +150,18 @@ func (self *Iterator[T]) Next() (*Iterable[T], error) {
 		return nil, nil
 	}
 
-	limit := self.InitialLimit
-	offset := self.InitialOffset
+	if self.lastResponse != nil && self.Concurrency > 1 {
+		return self.nextPrefetched()
+	}
 
-	offsetlimit := 0
+	limit := self.InitialLimit
 	if self.lastResponse != nil {
 		limit = self.lastResponse.Limit
-		offset = self.lastResponse.Offset
-
-		offsetlimit = offset + limit
 	}
 
-	response, err := self.next(limit, offsetlimit)
+	offset := self.nextOffset()
+	self.throttle()
+	response, err := self.next(self.capLimit(offset, limit), offset)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +170,89 @@ func (self *Iterator[T]) Next() (*Iterable[T], error) {
 	return response, nil
 }
 
+// nextOffset returns the offset of the page following the last fetched one.
+func (self *Iterator[T]) nextOffset() int {
+	if self.lastResponse == nil {
+		return self.InitialOffset
+	}
+
+	return self.lastResponse.Offset + self.lastResponse.Limit
+}
+
+// endOffset returns the offset at which the iteration stops.
+func (self *Iterator[T]) endOffset() int {
+	end := self.lastResponse.Count
+	if self.MaxItems > 0 && self.InitialOffset+self.MaxItems < end {
+		end = self.InitialOffset + self.MaxItems
+	}
+
+	return end
+}
+
+// capLimit shrinks the page limit so no items above MaxItems are requested.
+func (self *Iterator[T]) capLimit(offset int, limit int) int {
+	if self.MaxItems <= 0 {
+		return limit
+	}
+
+	rest := self.InitialOffset + self.MaxItems - offset
+	if limit <= 0 || rest < limit {
+		return rest
+	}
+
+	return limit
+}
+
+func (self *Iterator[T]) nextPrefetched() (*Iterable[T], error) {
+	if !self.prefetching {
+		self.prefetching = true
+		self.prefetchOffset = self.nextOffset()
+		self.prefetchLimit = self.lastResponse.Limit
+	}
+
+	for len(self.pending) < self.Concurrency && self.prefetchOffset < self.endOffset() {
+		self.throttle()
+
+		ch := make(chan prefetchResult[T], 1)
+		go func(limit int, offset int) {
+			response, err := self.next(limit, offset)
+			ch <- prefetchResult[T]{response: response, err: err}
+		}(self.capLimit(self.prefetchOffset, self.prefetchLimit), self.prefetchOffset)
+
+		self.pending = append(self.pending, ch)
+		self.prefetchOffset += self.prefetchLimit
+	}
+
+	if len(self.pending) == 0 {
+		self.prefetching = false
+		return nil, nil
+	}
+
+	result := <-self.pending[0]
+	self.pending = self.pending[1:]
+	if result.err != nil {
+		// drop pages fetched ahead, so the next call resumes right after the
+		// last successfully returned page
+		self.pending = nil
+		self.prefetching = false
+		return nil, result.err
+	}
+
+	self.lastResponse = result.response
+	return result.response, nil
+}
+
+func (self *Iterator[T]) throttle() {
+	if self.RequestInterval <= 0 {
+		return
+	}
+
+	if wait := self.RequestInterval - time.Since(self.lastRequest); wait > 0 {
+		time.Sleep(wait)
+	}
+	self.lastRequest = time.Now()
+}
+
 func (self *Iterator[T]) All() ([]*Iterable[T], error) {
 	var result []*Iterable[T]
 	for self.HasNext() {
@@ -150,6 +267,131 @@ func (self *Iterator[T]) All() ([]*Iterable[T], error) {
 	return result, nil
 }
 
+// CursorSnapshot describes the boundaries of a cursor listing. Entities
+// created after the listing started (id above MaxId) are never returned.
+type CursorSnapshot struct {
+	StartedAt time.Time
+	MaxId     int
+	FirstId   int
+	LastId    int
+	Count     int
+}
+
+// CursorIterator pages through entities sorted by id using _id__gt filters
+// instead of offsets, so every entity is seen exactly once even when entities
+// are created or deleted during the listing.
+type CursorIterator[E any] struct {
+	Limit    int
+	Snapshot CursorSnapshot
+	// RequestInterval is the minimal delay between requests.
+	RequestInterval time.Duration
+
+	started     bool
+	done        bool
+	lastRequest time.Time
+
+	options RequestOptions
+	fetch   func(options RequestOptions) (*Iterable[[]E], error)
+	id      func(E) int
+}
+
+func (self *CursorIterator[E]) HasNext() bool {
+	return !self.done
+}
+
+func (self *CursorIterator[E]) Next() (*Iterable[[]E], error) {
+	if self.done {
+		return nil, nil
+	}
+
+	if !self.started {
+		if err := self.start(); err != nil {
+			return nil, err
+		}
+
+		if self.done {
+			return &Iterable[[]E]{}, nil
+		}
+	}
+
+	option := self.options.Clone()
+	option.SetSorting([]string{"id"})
+	option.SetLimit(self.Limit)
+	option.Set("_id__lt", strconv.Itoa(self.Snapshot.MaxId+1))
+	if self.Snapshot.Count > 0 {
+		option.Set("_id__gt", strconv.Itoa(self.Snapshot.LastId))
+	}
+
+	self.throttle()
+	response, err := self.fetch(option)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response.Items) > 0 {
+		if self.Snapshot.Count == 0 {
+			self.Snapshot.FirstId = self.id(response.Items[0])
+		}
+
+		self.Snapshot.LastId = self.id(response.Items[len(response.Items)-1])
+		self.Snapshot.Count += len(response.Items)
+	}
+
+	// the api caps the page size, so a short page does not mean the end
+	if len(response.Items) == 0 || self.Snapshot.LastId >= self.Snapshot.MaxId {
+		self.done = true
+	}
+
+	return response, nil
+}
+
+func (self *CursorIterator[E]) All() ([]*Iterable[[]E], error) {
+	var result []*Iterable[[]E]
+	for self.HasNext() {
+		data, err := self.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, data)
+	}
+
+	return result, nil
+}
+
+func (self *CursorIterator[E]) start() error {
+	option := self.options.Clone()
+	option.SetSorting([]string{"-id"})
+	option.SetLimit(1)
+
+	self.throttle()
+	response, err := self.fetch(option)
+	if err != nil {
+		return err
+	}
+
+	self.started = true
+	self.Snapshot.StartedAt = time.Now()
+	if len(response.Items) == 0 {
+		self.done = true
+		return nil
+	}
+
+	self.Snapshot.MaxId = self.id(response.Items[0])
+	return nil
+}
+
+func (self *CursorIterator[E]) throttle() {
+	if self.RequestInterval <= 0 {
+		return
+	}
+
+	if wait := self.RequestInterval - time.Since(self.lastRequest); wait > 0 {
+		time.Sleep(wait)
+	}
+	self.lastRequest = time.Now()
+}
+
 func MakeContentOptions(tp ContentMethod, br *bufio.Reader) (opt ContentOptions, err error) {
 	peek, err := br.Peek(4096)
 	if err != nil {
@@ -0,0 +1,476 @@
+package vkads
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type pageCall struct {
+	limit  int
+	offset int
+}
+
+// fakePages serves total items numbered by their offset. The echoed limit is
+// capped at maxPage like the api does, or zero when echoZero is set.
+type fakePages struct {
+	total    int
+	maxPage  int
+	echoZero bool
+
+	mu    sync.Mutex
+	calls []pageCall
+}
+
+func (f *fakePages) next(limit int, offset int) (*Iterable[[]int], error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, pageCall{limit: limit, offset: offset})
+	f.mu.Unlock()
+
+	if f.maxPage > 0 && limit > f.maxPage {
+		limit = f.maxPage
+	}
+
+	response := &Iterable[[]int]{Count: f.total, Limit: limit, Offset: offset}
+	for i := offset; i < offset+limit && i < f.total; i++ {
+		response.Items = append(response.Items, i)
+	}
+	if f.echoZero {
+		response.Limit = 0
+	}
+
+	return response, nil
+}
+
+func TestIterator(t *testing.T) {
+	tests := []struct {
+		name     string
+		pages    *fakePages
+		iterator Iterator[[]int]
+
+		wantCalls []pageCall
+		wantFirst int
+		wantItems int
+	}{
+		{
+			name:      "empty result",
+			pages:     &fakePages{total: 0},
+			iterator:  Iterator[[]int]{InitialLimit: 50},
+			wantCalls: []pageCall{{50, 0}},
+		},
+		{
+			name:      "count below limit",
+			pages:     &fakePages{total: 10},
+			iterator:  Iterator[[]int]{InitialLimit: 50},
+			wantCalls: []pageCall{{50, 0}},
+			wantItems: 10,
+		},
+		{
+			name:      "several pages",
+			pages:     &fakePages{total: 120},
+			iterator:  Iterator[[]int]{InitialLimit: 50},
+			wantCalls: []pageCall{{50, 0}, {50, 50}, {50, 100}},
+			wantItems: 120,
+		},
+		{
+			name:      "initial offset",
+			pages:     &fakePages{total: 120},
+			iterator:  Iterator[[]int]{InitialLimit: 50, InitialOffset: 30},
+			wantCalls: []pageCall{{50, 30}, {50, 80}},
+			wantFirst: 30,
+			wantItems: 90,
+		},
+		{
+			name:      "max items in the middle of a page",
+			pages:     &fakePages{total: 200},
+			iterator:  Iterator[[]int]{InitialLimit: 50, MaxItems: 70},
+			wantCalls: []pageCall{{50, 0}, {20, 50}},
+			wantItems: 70,
+		},
+		{
+			name:      "max items with initial offset",
+			pages:     &fakePages{total: 200},
+			iterator:  Iterator[[]int]{InitialLimit: 50, InitialOffset: 10, MaxItems: 60},
+			wantCalls: []pageCall{{50, 10}, {10, 60}},
+			wantFirst: 10,
+			wantItems: 60,
+		},
+		{
+			name:      "max items above count",
+			pages:     &fakePages{total: 30},
+			iterator:  Iterator[[]int]{InitialLimit: 50, MaxItems: 100},
+			wantCalls: []pageCall{{50, 0}},
+			wantItems: 30,
+		},
+		{
+			name:      "echoed limit of zero",
+			pages:     &fakePages{total: 120, echoZero: true},
+			iterator:  Iterator[[]int]{InitialLimit: 50},
+			wantCalls: []pageCall{{50, 0}},
+			wantItems: 50,
+		},
+		{
+			name:      "limit capped by the api",
+			pages:     &fakePages{total: 600, maxPage: 250},
+			iterator:  Iterator[[]int]{InitialLimit: 500},
+			wantCalls: []pageCall{{500, 0}, {250, 250}, {250, 500}},
+			wantItems: 600,
+		},
+		{
+			name:      "prefetching",
+			pages:     &fakePages{total: 230},
+			iterator:  Iterator[[]int]{InitialLimit: 50, Concurrency: 3},
+			wantCalls: []pageCall{{50, 0}, {50, 50}, {50, 100}, {50, 150}, {50, 200}},
+			wantItems: 230,
+		},
+		{
+			name:      "prefetching with max items",
+			pages:     &fakePages{total: 500},
+			iterator:  Iterator[[]int]{InitialLimit: 50, MaxItems: 130, Concurrency: 4},
+			wantCalls: []pageCall{{50, 0}, {50, 50}, {30, 100}},
+			wantItems: 130,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pages := test.pages
+			it := test.iterator
+			it.next = pages.next
+
+			result, err := it.All()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var items []int
+			for _, page := range result {
+				items = append(items, page.Items...)
+			}
+
+			if len(items) != test.wantItems {
+				t.Fatalf("got %d items, want %d", len(items), test.wantItems)
+			}
+			for i, item := range items {
+				if item != test.wantFirst+i {
+					t.Fatalf("item %d is %d, want %d", i, item, test.wantFirst+i)
+				}
+			}
+
+			// prefetched pages are requested concurrently
+			sort.Slice(pages.calls, func(i, j int) bool { return pages.calls[i].offset < pages.calls[j].offset })
+			if !reflect.DeepEqual(pages.calls, test.wantCalls) {
+				t.Fatalf("got calls %v, want %v", pages.calls, test.wantCalls)
+			}
+
+			if it.HasNext() {
+				t.Fatal("HasNext is true after All")
+			}
+		})
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func TestCreateApiIteratorKeepsOptions(t *testing.T) {
+	var queries []url.Values
+	api := NewWithHttpClient(Token{}, &http.Client{Transport: roundTripFunc(func(request *http.Request) (*http.Response, error) {
+		query := request.URL.Query()
+		queries = append(queries, query)
+
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		body, _ := json.Marshal(Iterable[[]int]{Items: make([]int, limit), Count: 25, Limit: limit, Offset: offset})
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(string(body))),
+			Request:    request,
+		}, nil
+	})})
+
+	option := NewRequestOptions().SetLimit(10).SetOffset(5).SetStatus([]string{"active"})
+	before := option.Clone()
+
+	it := createApiIterator[[]int](api, "/api/v2/banners.json", option)
+	if _, err := it.All(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(option.Values, before.Values) {
+		t.Fatalf("options changed to %v", option.Values)
+	}
+
+	var offsets []string
+	for _, query := range queries {
+		offsets = append(offsets, query.Get("offset"))
+		if query.Get("_status") != "active" {
+			t.Fatalf("filter lost in %v", query)
+		}
+	}
+	if want := []string{"5", "15"}; !reflect.DeepEqual(offsets, want) {
+		t.Fatalf("got offsets %v, want %v", offsets, want)
+	}
+}
+
+// fakeEntities serves a list endpoint over entities keyed by id. It supports
+// the id and updated range filters, sorting, limit capped at maxPage and
+// offset. afterRequest is called after every served request with its number.
+type fakeEntities struct {
+	maxPage      int
+	items        map[int]map[string]interface{}
+	afterRequest func(n int)
+
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func newFakeEntities(ids ...int) *fakeEntities {
+	f := &fakeEntities{items: make(map[int]map[string]interface{})}
+	for _, id := range ids {
+		f.items[id] = map[string]interface{}{"id": id}
+	}
+
+	return f
+}
+
+func (f *fakeEntities) api() *Api {
+	return NewWithHttpClient(Token{}, &http.Client{Transport: f})
+}
+
+func (f *fakeEntities) RoundTrip(request *http.Request) (*http.Response, error) {
+	body, err := json.Marshal(f.serve(request.URL.Query()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(string(body))),
+		Request:    request,
+	}, nil
+}
+
+func (f *fakeEntities) serve(query url.Values) Iterable[[]map[string]interface{}] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queries = append(f.queries, query)
+	if f.afterRequest != nil {
+		defer f.afterRequest(len(f.queries))
+	}
+
+	intFilter := func(name string, match func(id int, bound int) bool) func(map[string]interface{}) bool {
+		return func(item map[string]interface{}) bool {
+			if query.Get(name) == "" {
+				return true
+			}
+			bound, _ := strconv.Atoi(query.Get(name))
+			return match(item["id"].(int), bound)
+		}
+	}
+	stringFilter := func(name string, field string, match func(value string, bound string) bool) func(map[string]interface{}) bool {
+		return func(item map[string]interface{}) bool {
+			if query.Get(name) == "" {
+				return true
+			}
+			value, _ := item[field].(string)
+			return match(value, query.Get(name))
+		}
+	}
+	filters := []func(map[string]interface{}) bool{
+		intFilter("_id__gt", func(id int, bound int) bool { return id > bound }),
+		intFilter("_id__lt", func(id int, bound int) bool { return id < bound }),
+		stringFilter("_updated__gt", "updated", func(value string, bound string) bool { return value > bound }),
+		stringFilter("_updated__lt", "updated", func(value string, bound string) bool { return value < bound }),
+	}
+
+	var items []map[string]interface{}
+	for _, item := range f.items {
+		matched := true
+		for _, filter := range filters {
+			matched = matched && filter(item)
+		}
+
+		if matched {
+			copied := make(map[string]interface{})
+			for key, value := range item {
+				copied[key] = value
+			}
+			items = append(items, copied)
+		}
+	}
+
+	sorting := strings.Split(query.Get("_sorting"), ",")
+	sort.Slice(items, func(i, j int) bool {
+		for _, key := range append(sorting, "id") {
+			desc := strings.HasPrefix(key, "-")
+			key = strings.TrimPrefix(key, "-")
+			if key == "" {
+				continue
+			}
+
+			a, b := fmt.Sprintf("%020v", items[i][key]), fmt.Sprintf("%020v", items[j][key])
+			if a != b {
+				return (a < b) != desc
+			}
+		}
+		return false
+	})
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+	if f.maxPage > 0 && limit > f.maxPage {
+		limit = f.maxPage
+	}
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	response := Iterable[[]map[string]interface{}]{Count: len(items), Limit: limit, Offset: offset}
+	for i := offset; i < offset+limit && i < len(items); i++ {
+		response.Items = append(response.Items, items[i])
+	}
+
+	return response
+}
+
+type idItem struct {
+	Id int `json:"id"`
+}
+
+func TestCursorIterator(t *testing.T) {
+	tests := []struct {
+		name     string
+		entities *fakeEntities
+		option   RequestOptions
+		// mutate changes the entities after the request n
+		mutate func(f *fakeEntities, n int)
+
+		wantIds      []int
+		wantRequests int
+	}{
+		{
+			name:         "empty first listing",
+			entities:     newFakeEntities(),
+			option:       NewRequestOptions().SetLimit(2),
+			wantRequests: 1,
+		},
+		{
+			name:         "several pages",
+			entities:     newFakeEntities(3, 1, 7, 5, 2),
+			option:       NewRequestOptions().SetLimit(2),
+			wantIds:      []int{1, 2, 3, 5, 7},
+			wantRequests: 4,
+		},
+		{
+			name:         "page size capped by the api",
+			entities:     &fakeEntities{maxPage: 2, items: newFakeEntities(1, 2, 3, 4, 5).items},
+			option:       NewRequestOptions().SetLimit(250),
+			wantIds:      []int{1, 2, 3, 4, 5},
+			wantRequests: 4,
+		},
+		{
+			name:     "max id deleted during the listing",
+			entities: newFakeEntities(1, 2, 3, 4, 5),
+			option:   NewRequestOptions().SetLimit(2),
+			mutate: func(f *fakeEntities, n int) {
+				if n == 2 {
+					delete(f.items, 5)
+				}
+			},
+			wantIds:      []int{1, 2, 3, 4},
+			wantRequests: 4,
+		},
+		{
+			name:     "entities created after the start",
+			entities: newFakeEntities(1, 2, 3),
+			option:   NewRequestOptions().SetLimit(2),
+			mutate: func(f *fakeEntities, n int) {
+				f.items[3+n] = map[string]interface{}{"id": 3 + n}
+			},
+			wantIds:      []int{1, 2, 3},
+			wantRequests: 3,
+		},
+		{
+			name:     "entities deleted before the cursor",
+			entities: newFakeEntities(1, 2, 3, 4, 5),
+			option:   NewRequestOptions().SetLimit(2),
+			mutate: func(f *fakeEntities, n int) {
+				if n == 2 {
+					delete(f.items, 1)
+				}
+			},
+			wantIds:      []int{1, 2, 3, 4, 5},
+			wantRequests: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entities := test.entities
+			if test.mutate != nil {
+				entities.afterRequest = func(n int) { test.mutate(entities, n) }
+			}
+
+			it := createApiCursorIterator(entities.api(), "/api/v2/banners.json", func(item idItem) int { return item.Id }, test.option)
+			result, err := it.All()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []int
+			for _, page := range result {
+				for _, item := range page.Items {
+					ids = append(ids, item.Id)
+				}
+			}
+
+			if !reflect.DeepEqual(ids, test.wantIds) {
+				t.Fatalf("got ids %v, want %v", ids, test.wantIds)
+			}
+			if len(entities.queries) != test.wantRequests {
+				t.Fatalf("got %d requests, want %d: %v", len(entities.queries), test.wantRequests, entities.queries)
+			}
+			if len(ids) > 0 && (it.Snapshot.FirstId != ids[0] || it.Snapshot.LastId != ids[len(ids)-1] || it.Snapshot.Count != len(ids)) {
+				t.Fatalf("unexpected snapshot %+v", it.Snapshot)
+			}
+			if it.HasNext() {
+				t.Fatal("HasNext is true after All")
+			}
+		})
+	}
+}
+
+func TestCursorIteratorRequestsIds(t *testing.T) {
+	entities := newFakeEntities(1, 2, 3)
+	option := NewRequestOptions().SetFields([]string{"name", "status"}).SetLimit(2)
+
+	it := createApiCursorIterator(entities.api(), "/api/v2/banners.json", func(item idItem) int { return item.Id }, option)
+	if _, err := it.All(); err != nil {
+		t.Fatal(err)
+	}
+
+	if option.Get("fields") != "name,status" {
+		t.Fatalf("caller options changed to %v", option.Values)
+	}
+	for _, query := range entities.queries {
+		if query.Get("fields") != "id,name,status" {
+			t.Fatalf("requested fields %q", query.Get("fields"))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package vkobj
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Fields is a list of api field names of the model T, it can be passed
+// wherever a plain []string of field names is expected.
+type Fields[T any] []string
+
+// AllFields returns the api field names modeled by the struct T, derived from
+// its json tags. Fields tagged `fields:"-"` are decoded but never requested.
+func AllFields[T any]() Fields[T] {
+	var result Fields[T]
+	for _, field := range jsonFields(reflect.TypeOf((*T)(nil)).Elem()) {
+		if field.skip {
+			continue
+		}
+		result = append(result, field.name)
+	}
+
+	return result
+}
+
+// SelectFields returns the given field names, failing on names not modeled by
+// the struct T.
+func SelectFields[T any](names ...string) (Fields[T], error) {
+	known := make(map[string]bool)
+	for _, name := range AllFields[T]() {
+		known[name] = true
+	}
+
+	var result Fields[T]
+	for _, name := range names {
+		if !known[name] {
+			var t T
+			return nil, fmt.Errorf("field %q is not modeled by %T", name, t)
+		}
+		result = append(result, name)
+	}
+
+	return result, nil
+}
+
+// MustSelectFields is like SelectFields but panics on unknown names, it is
+// meant for package level variables.
+func MustSelectFields[T any](names ...string) Fields[T] {
+	fields, err := SelectFields[T](names...)
+	if err != nil {
+		panic(err)
+	}
+
+	return fields
+}
+
+// UnknownFields returns the paths of json object keys in data which have no
+// matching field in v. Array elements are denoted by [] in the path.
+func UnknownFields(data []byte, v interface{}) ([]string, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	collectUnknownFields(reflect.TypeOf(v), raw, "", found)
+
+	var result []string
+	for path := range found {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+func collectUnknownFields(t reflect.Type, raw interface{}, path string, found map[string]bool) {
+	if t == nil {
+		return
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// custom decoders define their own format
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch val := raw.(type) {
+	case map[string]interface{}:
+		if t.Kind() == reflect.Map {
+			for key, item := range val {
+				collectUnknownFields(t.Elem(), item, joinFieldPath(path, key), found)
+			}
+			return
+		}
+
+		if t.Kind() != reflect.Struct {
+			return
+		}
+
+		fields := make(map[string]reflect.Type)
+		for _, field := range jsonFields(t) {
+			fields[strings.ToLower(field.name)] = field.typ
+		}
+
+		for key, item := range val {
+			ft, ok := fields[strings.ToLower(key)]
+			if !ok {
+				found[joinFieldPath(path, key)] = true
+				continue
+			}
+
+			collectUnknownFields(ft, item, joinFieldPath(path, key), found)
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+
+		for _, item := range val {
+			collectUnknownFields(t.Elem(), item, path+"[]", found)
+		}
+	}
+}
+
+func joinFieldPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+type jsonField struct {
+	name string
+	typ  reflect.Type
+	skip bool
+}
+
+func jsonFields(t reflect.Type) []jsonField {
+	var result []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				result = append(result, jsonFields(ft)...)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		result = append(result, jsonField{name: name, typ: field.Type, skip: field.Tag.Get("fields") == "-"})
+	}
+
+	return result
+}
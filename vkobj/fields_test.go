@@ -0,0 +1,105 @@
+package vkobj
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fieldsBase struct {
+	Id int `json:"id"`
+}
+
+type fieldsItem struct {
+	Name string `json:"name"`
+}
+
+type fieldsModel struct {
+	fieldsBase
+	Title    string                `json:"title,omitempty"`
+	Plain    int                   // named after the go field
+	Ignored  string                `json:"-"`
+	Decoded  []fieldsItem          `json:"decoded" fields:"-"`
+	Items    []fieldsItem          `json:"items"`
+	ByKey    map[string]fieldsItem `json:"by_key"`
+	Created  *DateTime             `json:"created"`
+	internal string
+}
+
+func TestAllFields(t *testing.T) {
+	got := AllFields[fieldsModel]()
+	want := Fields[fieldsModel]{"id", "title", "Plain", "items", "by_key", "created"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	for _, field := range AllFields[AdGroup]() {
+		if field == "banners" || field == "social" {
+			t.Errorf("ad group requests %q", field)
+		}
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	fields, err := SelectFields[fieldsModel]("title", "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fields, Fields[fieldsModel]{"title", "id"}) {
+		t.Fatalf("got %v", fields)
+	}
+
+	if _, err := SelectFields[fieldsModel]("id", "nope"); err == nil || !strings.Contains(err.Error(), `"nope"`) {
+		t.Fatalf("got error %v for an unknown field", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustSelectFields didn't panic on an unknown field")
+		}
+	}()
+	MustSelectFields[fieldsModel]("nope")
+}
+
+func TestUnknownFields(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{
+			name: "known",
+			json: `{"id":1,"Title":"x","plain":2,"decoded":[{"name":"a"}],"created":"2023-01-02 03:04:05"}`,
+		},
+		{
+			name: "top level",
+			json: `{"id":1,"foo":1,"-":2,"internal":"x"}`,
+			want: []string{"-", "foo", "internal"},
+		},
+		{
+			name: "nested",
+			json: `{"items":[{"name":"a","bar":1},{"baz":2}],"by_key":{"k":{"qux":3}}}`,
+			want: []string{"by_key.k.qux", "items[].bar", "items[].baz"},
+		},
+		{
+			name: "custom decoders are skipped",
+			json: `{"created":{"foo":1}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := UnknownFields([]byte(test.json), fieldsModel{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	if _, err := UnknownFields([]byte(`{`), fieldsModel{}); err == nil {
+		t.Fatal("no error for broken json")
+	}
+}
@@ -0,0 +1,53 @@
+package vkobj
+
+type LeadFormFieldType string
+
+const LeadFormFieldTypeName LeadFormFieldType = "name"
+const LeadFormFieldTypePhone LeadFormFieldType = "phone"
+const LeadFormFieldTypeEmail LeadFormFieldType = "email"
+const LeadFormFieldTypeCity LeadFormFieldType = "city"
+const LeadFormFieldTypeCustom LeadFormFieldType = "custom"
+
+type LeadFormField struct {
+	Key      string            `json:"key"`
+	Type     LeadFormFieldType `json:"type"`
+	Title    string            `json:"title,omitempty"`
+	Required bool              `json:"required,omitempty"`
+	Choices  []string          `json:"choices,omitempty"`
+}
+
+type LeadForm struct {
+	Id               int             `json:"id,omitempty"`
+	Name             string          `json:"name"`
+	Title            string          `json:"title"`
+	Description      string          `json:"description,omitempty"`
+	Fields           []LeadFormField `json:"fields"`
+	PrivacyPolicyUrl string          `json:"privacy_policy_url"`
+	ThanksText       string          `json:"thanks_text,omitempty"`
+	Status           string          `json:"status,omitempty"`
+	Created          *DateTime       `json:"created,omitempty"`
+}
+
+type LeadAnswer struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type Lead struct {
+	Id        int          `json:"id"`
+	FormId    int          `json:"form_id"`
+	AdPlanId  int          `json:"ad_plan_id"`
+	AdGroupId int          `json:"ad_group_id"`
+	BannerId  int          `json:"banner_id"`
+	Answers   []LeadAnswer `json:"answers"`
+	Created   *DateTime    `json:"created,omitempty"`
+}
+
+func (l Lead) Answer(key string) string {
+	for _, answer := range l.Answers {
+		if answer.Key == key {
+			return answer.Value
+		}
+	}
+	return ""
+}
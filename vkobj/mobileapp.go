@@ -0,0 +1,46 @@
+package vkobj
+
+type MobileAppPlatform string
+
+const MobileAppPlatformIos MobileAppPlatform = "ios"
+const MobileAppPlatformAndroid MobileAppPlatform = "android"
+
+// MinSkAdCampaignId and MaxSkAdCampaignId bound the campaign ids allowed by
+// SKAdNetwork.
+const MinSkAdCampaignId = 1
+const MaxSkAdCampaignId = 100
+
+type MobileApp struct {
+	Id          int               `json:"id,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Platform    MobileAppPlatform `json:"platform,omitempty"`
+	BundleId    string            `json:"bundle_id,omitempty"`
+	StoreUrl    string            `json:"store_url"`
+	TrackingUrl string            `json:"tracking_url,omitempty"`
+	Status      string            `json:"status,omitempty"`
+}
+
+// StoreUrlType returns the UrlTypes role of the app store url.
+func (a MobileApp) StoreUrlType() string {
+	if a.Platform == MobileAppPlatformIos {
+		return "ios_store_url"
+	}
+	return "android_store_url"
+}
+
+// TrackingUrlType returns the UrlTypes role of the tracking url.
+func (a MobileApp) TrackingUrlType() string {
+	if a.Platform == MobileAppPlatformIos {
+		return "ios_tracking_url"
+	}
+	return "android_tracking_url"
+}
+
+// Urls returns the app urls by their UrlTypes role.
+func (a MobileApp) Urls() map[string]string {
+	urls := map[string]string{a.StoreUrlType(): a.StoreUrl}
+	if a.TrackingUrl != "" {
+		urls[a.TrackingUrlType()] = a.TrackingUrl
+	}
+	return urls
+}
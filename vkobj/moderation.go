@@ -0,0 +1,74 @@
+package vkobj
+
+type IssueCode string
+
+const IssueCodeNoMoney IssueCode = "NO_MONEY"
+const IssueCodeBudgetLimit IssueCode = "BUDGET_LIMIT"
+const IssueCodeBudgetLimitDay IssueCode = "BUDGET_LIMIT_DAY"
+const IssueCodeStopped IssueCode = "STOPPED"
+const IssueCodeArchived IssueCode = "ARCHIVED"
+const IssueCodeNotStarted IssueCode = "NOT_STARTED"
+const IssueCodeEnded IssueCode = "ENDED"
+const IssueCodeOnModeration IssueCode = "ON_MODERATION"
+const IssueCodeBannerBanned IssueCode = "BANNED"
+const IssueCodeNoAllowedBanners IssueCode = "NO_ALLOWED_BANNERS"
+const IssueCodeNoActiveBanners IssueCode = "NO_ACTIVE_BANNERS"
+
+// Issue explains why a plan, group or banner doesn't serve. Arguments hold
+// the values referenced by Message, e.g. the limit that was reached.
+type Issue struct {
+	Code      IssueCode              `json:"code"`
+	Message   string                 `json:"message"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+type Issues []Issue
+
+func (i Issues) Has(code IssueCode) bool {
+	for _, issue := range i {
+		if issue.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+type ModerationStatus string
+
+const ModerationStatusPending ModerationStatus = "pending"
+const ModerationStatusAllowed ModerationStatus = "allowed"
+const ModerationStatusBanned ModerationStatus = "banned"
+const ModerationStatusDelayed ModerationStatus = "delayed"
+
+type ModerationReasonCode string
+
+const ModerationReasonCodeProhibitedGoods ModerationReasonCode = "prohibited_goods"
+const ModerationReasonCodeMisleading ModerationReasonCode = "misleading"
+const ModerationReasonCodeImageQuality ModerationReasonCode = "image_quality"
+const ModerationReasonCodeTextErrors ModerationReasonCode = "text_errors"
+const ModerationReasonCodeLandingUnavailable ModerationReasonCode = "landing_unavailable"
+const ModerationReasonCodeLandingMismatch ModerationReasonCode = "landing_mismatch"
+const ModerationReasonCodeMissingDisclaimer ModerationReasonCode = "missing_disclaimer"
+const ModerationReasonCodeTrademark ModerationReasonCode = "trademark"
+const ModerationReasonCodeLegal ModerationReasonCode = "legal"
+
+// ModerationReason is a rejection reason of a banner, Field is the banner
+// role (textblock, content or url) the moderator pointed at, if any.
+type ModerationReason struct {
+	Id        int                    `json:"id"`
+	Code      ModerationReasonCode   `json:"code"`
+	Message   string                 `json:"message"`
+	Field     string                 `json:"field,omitempty"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+type ModerationReasons []ModerationReason
+
+func (r ModerationReasons) Has(code ModerationReasonCode) bool {
+	for _, reason := range r {
+		if reason.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,367 @@
+package vkobj
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// moneyScale is the number of decimal places kept by Money.
+const moneyScale = 6
+
+var moneyPow = [moneyScale + 1]int64{1, 10, 100, 1000, 10000, 100000, 1000000}
+
+// maxMoneyValue bounds the absolute scaled value of every Money, it is a whole
+// number of units so rounding never leaves the range.
+const maxMoneyValue = math.MaxInt64 / 1000000 * 1000000
+
+var ErrMoneyOverflow = errors.New("money: amount out of range")
+
+// Money is an exact decimal amount of budgets and prices. It keeps the number
+// of decimal places and the json form (string or number) it was decoded from,
+// so values are sent back exactly as received. Money built in code is encoded
+// as a json string.
+//
+// Currency is not part of the json, set it from User.Currency with In.
+// Arithmetic on amounts of different currencies fails, as does arithmetic
+// leaving the range of about ±9.2e12 units.
+type Money struct {
+	Currency string
+
+	value  int64
+	places int
+	number bool
+}
+
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Money{}, nil
+	}
+
+	negative := false
+	if s[0] == '-' || s[0] == '+' {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Money{}, fmt.Errorf("invalid money amount %q", s)
+	}
+
+	if len(fracPart) > moneyScale {
+		if strings.Trim(fracPart[moneyScale:], "0") != "" {
+			return Money{}, fmt.Errorf("money amount %q has more than %d decimal places", s, moneyScale)
+		}
+		fracPart = fracPart[:moneyScale]
+	}
+
+	var units int64
+	if intPart != "" {
+		var err error
+		units, err = strconv.ParseInt(intPart, 10, 64)
+		if err != nil || units >= maxMoneyValue/moneyPow[moneyScale] {
+			return Money{}, fmt.Errorf("money amount %q is out of range", s)
+		}
+	}
+
+	var frac int64
+	if fracPart != "" {
+		frac, _ = strconv.ParseInt(fracPart, 10, 64)
+		frac *= moneyPow[moneyScale-len(fracPart)]
+	}
+
+	value := units*moneyPow[moneyScale] + frac
+	if negative {
+		value = -value
+	}
+
+	return Money{value: value, places: len(fracPart)}, nil
+}
+
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MoneyFromFloat rounds f to places decimal places.
+func MoneyFromFloat(f float64, places int) (Money, error) {
+	places = clampPlaces(places)
+	step := float64(moneyPow[moneyScale-places])
+	value := math.Round(f*float64(moneyPow[moneyScale])/step) * step
+	if math.IsNaN(value) || math.Abs(value) >= math.MaxInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	m := Money{value: int64(value), places: places}
+	if m.value > maxMoneyValue || m.value < -maxMoneyValue {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return m, nil
+}
+
+// MoneyFromMinor builds an amount from minor units, e.g. kopecks with places 2.
+func MoneyFromMinor(amount int64, places int) (Money, error) {
+	places = clampPlaces(places)
+	return moneyFromBig(big.NewInt(amount), moneyPow[moneyScale-places], Money{places: places})
+}
+
+func MoneyRef(m Money) *Money {
+	return &m
+}
+
+// In returns the amount in the currency.
+func (m Money) In(currency string) Money {
+	m.Currency = currency
+	return m
+}
+
+func (m Money) Places() int {
+	return m.places
+}
+
+func (m Money) IsZero() bool {
+	return m.value == 0
+}
+
+func (m Money) Sign() int {
+	switch {
+	case m.value < 0:
+		return -1
+	case m.value > 0:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case m.value < o.value:
+		return -1, nil
+	case m.value > o.value:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	return m.combine(o, o.value)
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	return m.combine(o, -o.value)
+}
+
+func (m Money) Neg() Money {
+	m.value = -m.value
+	return m
+}
+
+// Mul multiplies the amount by an integer factor.
+func (m Money) Mul(n int64) (Money, error) {
+	return moneyFromBig(new(big.Int).Mul(big.NewInt(m.value), big.NewInt(n)), 1, m)
+}
+
+// MulRatio multiplies the amount by num/den rounding half away from zero to
+// the places of the amount, e.g. to apply a percentage.
+func (m Money) MulRatio(num int64, den int64) (Money, error) {
+	if den == 0 {
+		return Money{}, errors.New("money: division by zero")
+	}
+
+	step := moneyPow[moneyScale-m.places]
+	a := new(big.Int).Mul(big.NewInt(m.value), big.NewInt(num))
+	b := new(big.Int).Mul(big.NewInt(den), big.NewInt(step))
+	if b.Sign() < 0 {
+		a.Neg(a)
+		b.Neg(b)
+	}
+
+	// round half away from zero
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Abs(r).Lsh(r, 1).Cmp(b) >= 0 {
+		q.Add(q, big.NewInt(int64(a.Sign())))
+	}
+
+	return moneyFromBig(q, step, m)
+}
+
+// Round rounds the amount half away from zero to places decimal places.
+func (m Money) Round(places int) Money {
+	places = clampPlaces(places)
+	step := moneyPow[moneyScale-places]
+	m.value = roundDiv(m.value, step) * step
+	m.places = places
+	return m
+}
+
+// Split divides the amount into n parts of its places which add up exactly to
+// the amount, e.g. a budget between ad groups. The first parts get the
+// remainder.
+func (m Money) Split(n int) []Money {
+	if n <= 0 {
+		return nil
+	}
+
+	step := moneyPow[moneyScale-m.places]
+	steps := m.value / step
+	base, rest := steps/int64(n), steps%int64(n)
+
+	parts := make([]Money, n)
+	for i := range parts {
+		part := base
+		if int64(i) < rest {
+			part++
+		} else if -int64(i) > rest {
+			part--
+		}
+
+		parts[i] = m
+		parts[i].value = part * step
+	}
+
+	return parts
+}
+
+func (m Money) Float64() float64 {
+	return float64(m.value) / float64(moneyPow[moneyScale])
+}
+
+func (m Money) String() string {
+	// unsigned, so even math.MinInt64 has an absolute value
+	abs := uint64(m.value)
+	sign := ""
+	if m.value < 0 {
+		abs = -abs
+		sign = "-"
+	}
+
+	s := sign + strconv.FormatUint(abs/uint64(moneyPow[moneyScale]), 10)
+	if m.places > 0 {
+		frac := fmt.Sprintf("%0*d", moneyScale, abs%uint64(moneyPow[moneyScale]))
+		s += "." + frac[:m.places]
+	}
+
+	return s
+}
+
+func (m *Money) UnmarshalJSON(b []byte) error {
+	value := string(b)
+	if value == "null" {
+		return nil
+	}
+
+	number := !strings.HasPrefix(value, `"`)
+	parsed, err := ParseMoney(strings.Trim(value, `"`))
+	if err != nil {
+		return err
+	}
+
+	parsed.Currency = m.Currency
+	parsed.number = number
+	*m = parsed
+	return nil
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	if m.number {
+		return []byte(m.String()), nil
+	}
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// combine adds delta to m, both are within maxMoneyValue so the bound checks
+// never wrap.
+func (m Money) combine(o Money, delta int64) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+
+	if delta > 0 && m.value > maxMoneyValue-delta || delta < 0 && m.value < -maxMoneyValue-delta {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	if m.Currency == "" {
+		m.Currency = o.Currency
+	}
+	if o.places > m.places {
+		m.places = o.places
+	}
+
+	m.value += delta
+	return m, nil
+}
+
+func (m Money) checkCurrency(o Money) error {
+	if m.Currency != "" && o.Currency != "" && m.Currency != o.Currency {
+		return fmt.Errorf("money: currency mismatch %s and %s", m.Currency, o.Currency)
+	}
+	return nil
+}
+
+// moneyFromBig returns m with the value v*scale, failing outside maxMoneyValue.
+func moneyFromBig(v *big.Int, scale int64, m Money) (Money, error) {
+	v = new(big.Int).Mul(v, big.NewInt(scale))
+	if v.CmpAbs(big.NewInt(maxMoneyValue)) > 0 {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	m.value = v.Int64()
+	return m, nil
+}
+
+func roundDiv(a int64, b int64) int64 {
+	if b < 0 {
+		a, b = -a, -b
+	}
+
+	q, r := a/b, a%b
+	if r < 0 {
+		r = -r
+	}
+
+	if 2*r >= b {
+		if a < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+
+	return q
+}
+
+func clampPlaces(places int) int {
+	if places < 0 {
+		return 0
+	}
+	if places > moneyScale {
+		return moneyScale
+	}
+	return places
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
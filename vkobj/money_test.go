@@ -0,0 +1,195 @@
+package vkobj
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: "", want: "0"},
+		{in: "100", want: "100"},
+		{in: "100.50", want: "100.50"},
+		{in: " -0.5 ", want: "-0.5"},
+		{in: "+12.", want: "12"},
+		{in: ".25", want: "0.25"},
+		{in: "1.2300000", want: "1.230000"},
+		{in: "9223372036853.999999", want: "9223372036853.999999"},
+		{in: "9223372036854", err: true},
+		{in: "1.0000001", err: true},
+		{in: "1,5", err: true},
+		{in: "1e3", err: true},
+		{in: ".", err: true},
+		{in: "-", err: true},
+	}
+
+	for _, test := range tests {
+		m, err := ParseMoney(test.in)
+		if test.err {
+			if err == nil {
+				t.Errorf("ParseMoney(%q) = %s, want error", test.in, m)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseMoney(%q): %v", test.in, err)
+			continue
+		}
+		if m.String() != test.want {
+			t.Errorf("ParseMoney(%q) = %s, want %s", test.in, m, test.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	type budget struct {
+		Limit *Money `json:"limit,omitempty"`
+		Day   Money  `json:"day"`
+	}
+
+	tests := []string{
+		`{"limit":"1500.00","day":"100"}`,
+		`{"limit":1500.5,"day":0}`,
+		`{"day":"0.000001"}`,
+	}
+
+	for _, in := range tests {
+		var b budget
+		if err := json.Unmarshal([]byte(in), &b); err != nil {
+			t.Fatalf("unmarshal %s: %v", in, err)
+		}
+
+		out, err := json.Marshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != in {
+			t.Errorf("round trip of %s gave %s", in, out)
+		}
+	}
+
+	out, _ := json.Marshal(MustParseMoney("12.30"))
+	if string(out) != `"12.30"` {
+		t.Errorf("parsed money is encoded as %s, want a string", out)
+	}
+}
+
+func TestMoneySplit(t *testing.T) {
+	tests := []struct {
+		amount string
+		n      int
+		want   []string
+	}{
+		{amount: "100.00", n: 3, want: []string{"33.34", "33.33", "33.33"}},
+		{amount: "-100.00", n: 3, want: []string{"-33.34", "-33.33", "-33.33"}},
+		{amount: "10", n: 4, want: []string{"3", "3", "2", "2"}},
+		{amount: "0.01", n: 2, want: []string{"0.01", "0.00"}},
+		{amount: "5", n: 0},
+	}
+
+	for _, test := range tests {
+		parts := MustParseMoney(test.amount).Split(test.n)
+
+		var got []string
+		sum := Money{}
+		for _, part := range parts {
+			got = append(got, part.String())
+			sum, _ = sum.Add(part)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s split in %d = %v, want %v", test.amount, test.n, got, test.want)
+		}
+		if test.n > 0 {
+			if c, _ := sum.Cmp(MustParseMoney(test.amount)); c != 0 {
+				t.Errorf("%s split in %d adds up to %s", test.amount, test.n, sum)
+			}
+		}
+	}
+}
+
+func TestMoneyMulRatio(t *testing.T) {
+	tests := []struct {
+		amount   string
+		num, den int64
+		want     string
+	}{
+		{amount: "100.00", num: 15, den: 100, want: "15.00"},
+		{amount: "10.00", num: 1, den: 3, want: "3.33"},
+		{amount: "0.05", num: 1, den: 2, want: "0.03"},
+		{amount: "-0.05", num: 1, den: 2, want: "-0.03"},
+		{amount: "0.05", num: 1, den: -2, want: "-0.03"},
+		{amount: "1", num: 2, den: 3, want: "1"},
+	}
+
+	for _, test := range tests {
+		got, err := MustParseMoney(test.amount).MulRatio(test.num, test.den)
+		if err != nil {
+			t.Errorf("%s * %d/%d: %v", test.amount, test.num, test.den, err)
+			continue
+		}
+		if got.String() != test.want {
+			t.Errorf("%s * %d/%d = %s, want %s", test.amount, test.num, test.den, got, test.want)
+		}
+	}
+
+	if _, err := MustParseMoney("1").MulRatio(1, 0); err == nil {
+		t.Error("division by zero succeeded")
+	}
+}
+
+func TestMoneyOverflow(t *testing.T) {
+	big := MustParseMoney("9000000000000")
+
+	if m, err := MoneyFromFloat(1e13, 2); err == nil {
+		t.Errorf("MoneyFromFloat(1e13) = %s, want error", m)
+	}
+	if m, err := MoneyFromFloat(math.NaN(), 2); err == nil {
+		t.Errorf("MoneyFromFloat(NaN) = %s, want error", m)
+	}
+	if m, err := MoneyFromMinor(math.MaxInt64, 0); err == nil {
+		t.Errorf("MoneyFromMinor(MaxInt64) = %s, want error", m)
+	}
+	if m, err := big.Add(big); err == nil {
+		t.Errorf("Add = %s, want error", m)
+	}
+	if m, err := big.Neg().Sub(big); err == nil {
+		t.Errorf("Sub = %s, want error", m)
+	}
+	if m, err := big.Mul(2); err == nil {
+		t.Errorf("Mul = %s, want error", m)
+	}
+	if m, err := big.MulRatio(math.MaxInt64, 1); err == nil {
+		t.Errorf("MulRatio = %s, want error", m)
+	}
+	if m, err := MoneyFromFloat(-12.345, 2); err != nil || m.String() != "-12.35" {
+		t.Errorf("MoneyFromFloat(-12.345) = %s, %v", m, err)
+	}
+	if s := (Money{value: math.MinInt64, places: 6}).String(); s != "-9223372036854.775808" {
+		t.Errorf("MinInt64 is printed as %s", s)
+	}
+}
+
+func TestMoneyCurrency(t *testing.T) {
+	rub := MustParseMoney("1").In("RUB")
+	usd := MustParseMoney("1").In("USD")
+
+	if _, err := rub.Add(usd); err == nil {
+		t.Error("Add of different currencies succeeded")
+	}
+	if _, err := rub.Cmp(usd); err == nil {
+		t.Error("Cmp of different currencies succeeded")
+	}
+
+	sum, err := rub.Add(MustParseMoney("0.5"))
+	if err != nil || sum.Currency != "RUB" || sum.String() != "1.5" {
+		t.Errorf("Add = %s %s, %v", sum, sum.Currency, err)
+	}
+}
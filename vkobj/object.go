@@ -10,18 +10,20 @@ type AdditionalUserInfo struct {
 }
 
 type User struct {
-	Id            int      `json:"id"`
-	Username      string   `json:"username"`
-	Language      string   `json:"language"`
-	Firstname     string   `json:"firstname"`
-	Lastname      string   `json:"lastname"`
-	Email         string   `json:"email"`
-	Types         []string `json:"types"`
-	Status        string   `json:"status"`
-	InfoCurrency  string   `json:"info_currency"`
-	Currency      string   `json:"currency"`
-	Timezone      int      `json:"timezone"`
-	Country       int      `json:"country"`
+	Id           int      `json:"id"`
+	Username     string   `json:"username"`
+	Language     string   `json:"language"`
+	Firstname    string   `json:"firstname"`
+	Lastname     string   `json:"lastname"`
+	Email        string   `json:"email"`
+	Types        []string `json:"types"`
+	Status       string   `json:"status"`
+	InfoCurrency string   `json:"info_currency"`
+	Currency     string   `json:"currency"`
+	// Timezone is passed through as is, its unit and meaning are not
+	// documented, so it isn't used for time conversions.
+	Timezone      int `json:"timezone"`
+	Country       int `json:"country"`
 	EmailSettings []struct {
 		Type                    string `json:"type"`
 		Email                   string `json:"email"`
@@ -63,6 +65,12 @@ type User struct {
 	} `json:"regions"`
 }
 
+// Money parses an amount in the account currency.
+func (u User) Money(amount string) (Money, error) {
+	m, err := ParseMoney(amount)
+	return m.In(u.Currency), err
+}
+
 type AgencyClient struct {
 	AccessType string `json:"access_type"`
 	Status     string `json:"status"`
@@ -81,15 +89,13 @@ type AdPlanOrStatus struct {
 	Status      string   `json:"status"`
 }
 
-type AdPlanIssue struct {
-	Arguments struct{} `json:"arguments"`
-	Code      string   `json:"code"`
-	Message   string   `json:"message"`
-}
+type AdPlanIssue = Issue
 
 type Objective string
 
 const ObjectiveSiteConversions Objective = "site_conversions"
+const ObjectiveAppInstalls Objective = "app_installs"
+const ObjectiveInAppConversions Objective = "in_app_conversions"
 
 type UrlTypes = struct {
 	Primary                 [][]string `json:"primary,omitempty"`
@@ -146,83 +152,23 @@ const AdPlanStatusDeleted AdPlanStatus = "deleted"
 const AdPlanStatusBlocked AdPlanStatus = "blocked"
 
 type AdPlan struct {
-	Id              int             `json:"id,omitempty"`
-	Name            string          `json:"name"`
-	Status          AdPlanStatus    `json:"status,omitempty"`
-	VkadsStatus     interface{}     `json:"vkads_status,omitempty"`
-	Objective       Objective       `json:"objective,omitempty"`
-	AutobiddingMode AutobiddingMode `json:"autobidding_mode,omitempty"`
-	BudgetLimit     *float64        `json:"budget_limit,omitempty"`
-	BudgetLimitDay  *float64        `json:"budget_limit_day,omitempty"`
-	MaxPrice        *float64        `json:"max_price,string,omitempty"`
-	DateStart       Date            `json:"date_start,omitempty"`
-	DateEnd         *Date           `json:"date_end,omitempty"`
-	PricedGoal      PricedGoal      `json:"priced_goal,omitempty"`
-	AdGroups        []AdGroup       `json:"ad_groups,omitempty"`
-	Created         string          `json:"created,omitempty"`
-	Updated         string          `json:"updated,omitempty"`
-}
-
-type AgeTargeting struct {
-	AgeList []int `json:"age_list"`
-	Expand  bool  `json:"expand"`
-}
-
-func AgeList(from int, to int) []int {
-	var s []int
-	for i := from; i <= to; i++ {
-		s = append(s, i)
-	}
-
-	return s
-}
-
-var DefaultAgeList = AgeList(12, 75)
-
-type FulltimeTargetingFlag = string
-
-const FulltimeTargetingFlagUseHolidaysMoving = "use_holidays_moving"
-const FulltimeTargetingFlagCrossTimezone = "cross_timezone"
-
-type FulltimeTargeting struct {
-	Flags []FulltimeTargetingFlag `json:"flags"`
-	Fri   []int                   `json:"fri"`
-	Mon   []int                   `json:"mon"`
-	Sat   []int                   `json:"sat"`
-	Sun   []int                   `json:"sun"`
-	Thu   []int                   `json:"thu"`
-	Tue   []int                   `json:"tue"`
-	Wed   []int                   `json:"wed"`
-}
-
-var DefaultFulltimeTargetings = FulltimeTargeting{
-	Flags: []FulltimeTargetingFlag{FulltimeTargetingFlagUseHolidaysMoving, FulltimeTargetingFlagCrossTimezone},
-	Fri:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
-	Mon:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
-	Sat:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
-	Sun:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
-	Thu:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
-	Tue:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
-	Wed:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
-}
-
-type GeoTargeting struct {
-	Regions []int `json:"regions"`
-}
-
-type Sex string
-
-const SexMale Sex = "male"
-const SexFemale Sex = "female"
-
-type Targetings struct {
-	Age       AgeTargeting      `json:"age,omitempty"`
-	Fulltime  FulltimeTargeting `json:"fulltime,omitempty"`
-	Geo       GeoTargeting      `json:"geo,omitempty"`
-	Interests []int             `json:"interests,omitempty"`
-	Pads      []int             `json:"pads,omitempty"`
-	Segments  []int             `json:"segments,omitempty"`
-	Sex       []Sex             `json:"sex,omitempty"`
+	Id              int                `json:"id,omitempty"`
+	Name            string             `json:"name"`
+	Status          AdPlanStatus       `json:"status,omitempty"`
+	VkadsStatus     *AdPlanVkadsStatus `json:"vkads_status,omitempty"`
+	Issues          Issues             `json:"issues,omitempty"`
+	Objective       Objective          `json:"objective,omitempty"`
+	AutobiddingMode AutobiddingMode    `json:"autobidding_mode,omitempty"`
+	BudgetLimit     *Money             `json:"budget_limit,omitempty"`
+	BudgetLimitDay  *Money             `json:"budget_limit_day,omitempty"`
+	MaxPrice        *Money             `json:"max_price,omitempty"`
+	DateStart       Date               `json:"date_start,omitempty"`
+	DateEnd         *Date              `json:"date_end,omitempty"`
+	PricedGoal      PricedGoal         `json:"priced_goal,omitempty"`
+	PricelistId     int                `json:"pricelist_id,omitempty"`
+	AdGroups        []AdGroup          `json:"ad_groups,omitempty"`
+	Created         *DateTime          `json:"created,omitempty"`
+	Updated         *DateTime          `json:"updated,omitempty"`
 }
 
 type AgeRestriction string
@@ -234,26 +180,42 @@ const AgeRestriction16 AgeRestriction = "16+"
 const AgeRestriction18 AgeRestriction = "18+"
 
 type AdGroup struct {
-	Id              int             `json:"id,omitempty"`
-	Name            string          `json:"name"`
-	Status          string          `json:"status,omitempty"`
-	AdPlanId        Int             `json:"ad_plan_id,omitempty"`
-	PackageId       int             `json:"package_id,omitempty"`
-	AgeRestrictions AgeRestriction  `json:"age_restrictions,omitempty"`
-	AutobiddingMode AutobiddingMode `json:"autobidding_mode,omitempty"`
-	BudgetLimit     *Float64        `json:"budget_limit,omitempty"`
-	BudgetLimitDay  *Float64        `json:"budget_limit_day,omitempty"`
-	MaxPrice        *Float64        `json:"max_price,omitempty"`
-	DateStart       Date            `json:"date_start,omitempty"`
-	DateEnd         *Date           `json:"date_end,omitempty"`
-	Objective       Objective       `json:"objective,omitempty"`
-	EnableUtm       bool            `json:"enable_utm,omitempty"`
-	Utm             *string         `json:"utm,omitempty"`
-	Social          bool            `json:"social,omitempty"`
-	Targetings      Targetings      `json:"targetings,omitempty"`
-	Banners         []Banner        `json:"banners"`
-	Created         string          `json:"created,omitempty"`
-	Updated         string          `json:"updated,omitempty"`
+	Id                         int             `json:"id,omitempty"`
+	Name                       string          `json:"name"`
+	Status                     string          `json:"status,omitempty"`
+	AdPlanId                   Int             `json:"ad_plan_id,omitempty"`
+	PackageId                  int             `json:"package_id,omitempty"`
+	AgeRestrictions            AgeRestriction  `json:"age_restrictions,omitempty"`
+	AutobiddingMode            AutobiddingMode `json:"autobidding_mode,omitempty"`
+	BudgetLimit                *Money          `json:"budget_limit,omitempty"`
+	BudgetLimitDay             *Money          `json:"budget_limit_day,omitempty"`
+	MaxPrice                   *Money          `json:"max_price,omitempty"`
+	DateStart                  Date            `json:"date_start,omitempty"`
+	DateEnd                    *Date           `json:"date_end,omitempty"`
+	Objective                  Objective       `json:"objective,omitempty"`
+	PricelistId                int             `json:"pricelist_id,omitempty"`
+	MarketplaceAppClientId     int             `json:"marketplace_app_client_id,omitempty"`
+	SkAdCampaignId             *int            `json:"sk_ad_campaign_id,omitempty"`
+	Issues                     Issues          `json:"issues,omitempty"`
+	AuditPixels                interface{}     `json:"audit_pixels,omitempty"`
+	BannerUniqShowsLimit       int             `json:"banner_uniq_shows_limit,omitempty"`
+	UniqShowsLimit             int             `json:"uniq_shows_limit,omitempty"`
+	UniqShowsPeriod            string          `json:"uniq_shows_period,omitempty"`
+	Delivery                   string          `json:"delivery,omitempty"`
+	Language                   string          `json:"language,omitempty"`
+	Price                      *Money          `json:"price,omitempty"`
+	PricedGoal                 *PricedGoal     `json:"priced_goal,omitempty"`
+	PackagePricedEventType     int             `json:"package_priced_event_type,omitempty"`
+	EnableOfflineGoals         bool            `json:"enable_offline_goals,omitempty"`
+	DynamicBannersUseStorelink bool            `json:"dynamic_banners_use_storelink,omitempty"`
+	DynamicWithoutRemarketing  bool            `json:"dynamic_without_remarketing,omitempty"`
+	EnableUtm                  bool            `json:"enable_utm,omitempty"`
+	Utm                        *string         `json:"utm,omitempty"`
+	Social                     bool            `json:"social,omitempty" fields:"-"`
+	Targetings                 Targetings      `json:"targetings,omitempty"`
+	Banners                    []Banner        `json:"banners" fields:"-"`
+	Created                    *DateTime       `json:"created,omitempty"`
+	Updated                    *DateTime       `json:"updated,omitempty"`
 }
 
 type ContentType = string
@@ -296,16 +258,19 @@ type Urls struct {
 }
 
 type Banner struct {
-	Id               int                      `json:"id,omitempty"`
-	Name             string                   `json:"name"`
-	Status           string                   `json:"status,omitempty"`
-	AdGroupId        int                      `json:"ad_group_id,omitempty"`
-	Content          map[string]BannerContent `json:"content,omitempty"`
-	Delivery         string                   `json:"delivery,omitempty"`
-	Issues           interface{}              `json:"issues,omitempty"`
-	ModerationStatus string                   `json:"moderation_status,omitempty"`
-	Textblocks       map[string]Textblock     `json:"textblocks,omitempty"`
-	Urls             map[string]Urls          `json:"urls,omitempty"`
+	Id                int                      `json:"id,omitempty"`
+	Name              string                   `json:"name"`
+	Status            string                   `json:"status,omitempty"`
+	AdGroupId         int                      `json:"ad_group_id,omitempty"`
+	Content           map[string]BannerContent `json:"content,omitempty"`
+	Delivery          string                   `json:"delivery,omitempty"`
+	Issues            Issues                   `json:"issues,omitempty"`
+	ModerationStatus  ModerationStatus         `json:"moderation_status,omitempty"`
+	ModerationReasons ModerationReasons        `json:"moderation_reasons,omitempty"`
+	Textblocks        map[string]Textblock     `json:"textblocks,omitempty"`
+	Urls              map[string]Urls          `json:"urls,omitempty"`
+	Created           *DateTime                `json:"created,omitempty"`
+	Updated           *DateTime                `json:"updated,omitempty"`
 }
 
 type Region struct {
@@ -336,32 +301,6 @@ type TargetingsTree struct {
 
 type TargetingsTreeResponse []TargetingsTree
 
-type SegmentRelation struct {
-	Id         int         `json:"id"`
-	ObjectId   int         `json:"object_id"`
-	ObjectType string      `json:"object_type"`
-	Params     interface{} `json:"params"`
-}
-
-type SegmentUser struct {
-	Id       int    `json:"id"`
-	Type     string `json:"type"`
-	Username string `json:"username"`
-}
-
-type Segment struct {
-	Id             int               `json:"id"`
-	Name           string            `json:"name"`
-	CampaignIds    []int             `json:"campaign_ids"`
-	Flags          []string          `json:"flags"`
-	PassCondition  int               `json:"pass_condition"`
-	Relations      []SegmentRelation `json:"relations"`
-	RelationsCount int               `json:"relations_count"`
-	Users          []SegmentUser     `json:"users"`
-	Created        DateTime          `json:"created"`
-	Updated        DateTime          `json:"updated"`
-}
-
 type PackagePad struct {
 	Id          int    `json:"id"`
 	Name        string `json:"name"`
@@ -378,10 +317,10 @@ type Package struct {
 	Id                      int         `json:"id"`
 	Name                    string      `json:"name"`
 	Description             string      `json:"description"`
-	Price                   string      `json:"price"`
+	Price                   Money       `json:"price"`
 	PricedEventType         int         `json:"priced_event_type"`
 	PaidEventType           int         `json:"paid_event_type"`
-	MaxPricePerUnit         string      `json:"max_price_per_unit"`
+	MaxPricePerUnit         Money       `json:"max_price_per_unit"`
 	MaxUniqShowsLimit       int         `json:"max_uniq_shows_limit"`
 	MaxBannersInOneCampaign interface{} `json:"max_banners_in_one_campaign"`
 	RelatedPackageIds       []int       `json:"related_package_ids"`
@@ -393,8 +332,8 @@ type Package struct {
 	UrlTypes                UrlTypes    `json:"url_types"`
 	Status                  string      `json:"status"`
 	Objective               []string    `json:"objective"`
-	Created                 string      `json:"created"`
-	Updated                 string      `json:"updated"`
+	Created                 *DateTime   `json:"created,omitempty"`
+	Updated                 *DateTime   `json:"updated,omitempty"`
 }
 
 type TopmainlruGoal struct {
@@ -0,0 +1,35 @@
+package vkobj
+
+type PricelistStatus string
+
+const PricelistStatusProcessing PricelistStatus = "processing"
+const PricelistStatusActive PricelistStatus = "active"
+const PricelistStatusError PricelistStatus = "error"
+const PricelistStatusDeleted PricelistStatus = "deleted"
+
+type PricelistFormat string
+
+const PricelistFormatYml PricelistFormat = "yml"
+const PricelistFormatCsv PricelistFormat = "csv"
+
+type PricelistError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	OfferId string `json:"offer_id,omitempty"`
+	Line    int    `json:"line,omitempty"`
+}
+
+// Pricelist is a product feed used by dynamic remarketing. Feeds loaded from
+// Url are refreshed by the api every RefreshPeriod hours.
+type Pricelist struct {
+	Id            int              `json:"id,omitempty"`
+	Name          string           `json:"name"`
+	Url           string           `json:"url,omitempty"`
+	Format        PricelistFormat  `json:"format,omitempty"`
+	RefreshPeriod int              `json:"refresh_period,omitempty"`
+	Status        PricelistStatus  `json:"status,omitempty"`
+	OffersCount   int              `json:"offers_count,omitempty"`
+	Errors        []PricelistError `json:"errors,omitempty"`
+	Created       *DateTime        `json:"created,omitempty"`
+	Updated       *DateTime        `json:"updated,omitempty"`
+}
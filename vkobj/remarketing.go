@@ -0,0 +1,76 @@
+package vkobj
+
+type UsersListType string
+
+const UsersListTypeEmail UsersListType = "email"
+const UsersListTypePhone UsersListType = "phone"
+const UsersListTypeVk UsersListType = "vk"
+const UsersListTypeGaid UsersListType = "gaid"
+const UsersListTypeIdfa UsersListType = "idfa"
+
+type UsersListStatus string
+
+const UsersListStatusProcessing UsersListStatus = "processing"
+const UsersListStatusReady UsersListStatus = "ready"
+const UsersListStatusError UsersListStatus = "error"
+
+type UsersList struct {
+	Id           int             `json:"id"`
+	Name         string          `json:"name"`
+	Type         UsersListType   `json:"type"`
+	Status       UsersListStatus `json:"status"`
+	EntriesCount int             `json:"entries_count"`
+	Created      *DateTime       `json:"created,omitempty"`
+}
+
+type RemarketingCounter struct {
+	Id        int       `json:"id"`
+	CounterId int       `json:"counter_id"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Created   *DateTime `json:"created,omitempty"`
+}
+
+type CounterGoal struct {
+	Id          int    `json:"id"`
+	CounterId   int    `json:"counter_id"`
+	Goal        string `json:"goal"`
+	Description string `json:"description"`
+}
+
+// SegmentRelation returns a relation matching users who reached the goal
+// between left and right days ago.
+func (g CounterGoal) SegmentRelation(left int, right int) SegmentRelation {
+	return NewSegmentRelation(g.Id, CounterGoalRelationParams{
+		Type:  SegmentRelationTypePositive,
+		Left:  left,
+		Right: right,
+	})
+}
+
+// PricedGoal returns the goal to optimize an ad plan for.
+func (g CounterGoal) PricedGoal() PricedGoal {
+	return PricedGoal{Name: g.Goal, SourceID: g.CounterId}
+}
+
+// ContextPhrases is a search phrases audience: users who searched for any of
+// Phrases and none of MinusPhrases during the last Period days.
+type ContextPhrases struct {
+	Id           int      `json:"id,omitempty"`
+	Name         string   `json:"name"`
+	Phrases      []string `json:"phrases"`
+	MinusPhrases []string `json:"minus_phrases,omitempty"`
+	Period       int      `json:"period,omitempty"`
+	Status       string   `json:"status,omitempty"`
+}
+
+func (c ContextPhrases) SegmentRelation() SegmentRelation {
+	return NewSegmentRelation(c.Id, ContextPhrasesRelationParams{Type: SegmentRelationTypePositive})
+}
+
+type VkGroup struct {
+	Id           int    `json:"id"`
+	Name         string `json:"name"`
+	Url          string `json:"url"`
+	MembersCount int    `json:"members_count"`
+}
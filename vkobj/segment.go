@@ -0,0 +1,242 @@
+package vkobj
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+type SegmentObjectType string
+
+const SegmentObjectTypeUsersList SegmentObjectType = "remarketing_users_list"
+const SegmentObjectTypeCounter SegmentObjectType = "remarketing_counter"
+const SegmentObjectTypeCounterGoal SegmentObjectType = "remarketing_counter_goal"
+const SegmentObjectTypeContextPhrases SegmentObjectType = "remarketing_context_phrases"
+const SegmentObjectTypeVkGroup SegmentObjectType = "remarketing_vk_group"
+const SegmentObjectTypeApp SegmentObjectType = "remarketing_player"
+
+type SegmentRelationType string
+
+const SegmentRelationTypePositive SegmentRelationType = "positive"
+const SegmentRelationTypeNegative SegmentRelationType = "negative"
+
+// SegmentRelationParams are the object type specific params of a segment
+// relation.
+type SegmentRelationParams interface {
+	SegmentObjectType() SegmentObjectType
+}
+
+var segmentRelationParamsFactories = map[SegmentObjectType]func() SegmentRelationParams{
+	SegmentObjectTypeUsersList:      func() SegmentRelationParams { return &UsersListRelationParams{} },
+	SegmentObjectTypeCounter:        func() SegmentRelationParams { return &CounterRelationParams{} },
+	SegmentObjectTypeCounterGoal:    func() SegmentRelationParams { return &CounterGoalRelationParams{} },
+	SegmentObjectTypeContextPhrases: func() SegmentRelationParams { return &ContextPhrasesRelationParams{} },
+	SegmentObjectTypeVkGroup:        func() SegmentRelationParams { return &VkGroupRelationParams{} },
+	SegmentObjectTypeApp:            func() SegmentRelationParams { return &AppRelationParams{} },
+}
+
+type UsersListRelationParams struct {
+	Type SegmentRelationType `json:"type"`
+}
+
+func (UsersListRelationParams) SegmentObjectType() SegmentObjectType {
+	return SegmentObjectTypeUsersList
+}
+
+// CounterRelationParams selects users who visited a site tagged with the
+// counter between Left and Right days ago, whatever goals they reached.
+type CounterRelationParams struct {
+	Type  SegmentRelationType `json:"type"`
+	Left  int                 `json:"left"`
+	Right int                 `json:"right"`
+}
+
+func (CounterRelationParams) SegmentObjectType() SegmentObjectType {
+	return SegmentObjectTypeCounter
+}
+
+// CounterGoalRelationParams selects users who reached the goal between Left
+// and Right days ago.
+type CounterGoalRelationParams struct {
+	Type  SegmentRelationType `json:"type"`
+	Left  int                 `json:"left"`
+	Right int                 `json:"right"`
+}
+
+func (CounterGoalRelationParams) SegmentObjectType() SegmentObjectType {
+	return SegmentObjectTypeCounterGoal
+}
+
+type ContextPhrasesRelationParams struct {
+	Type SegmentRelationType `json:"type"`
+}
+
+func (ContextPhrasesRelationParams) SegmentObjectType() SegmentObjectType {
+	return SegmentObjectTypeContextPhrases
+}
+
+type VkGroupEvent string
+
+const VkGroupEventJoin VkGroupEvent = "join"
+const VkGroupEventLeave VkGroupEvent = "leave"
+const VkGroupEventVisit VkGroupEvent = "visit"
+const VkGroupEventLike VkGroupEvent = "like"
+const VkGroupEventComment VkGroupEvent = "comment"
+const VkGroupEventRepost VkGroupEvent = "repost"
+
+// VkGroupRelationParams selects users of a VK community. Without Events only
+// current members match, otherwise users who made any of Events between Left
+// and Right days ago.
+type VkGroupRelationParams struct {
+	Type   SegmentRelationType `json:"type"`
+	Events []VkGroupEvent      `json:"events,omitempty"`
+	Left   int                 `json:"left,omitempty"`
+	Right  int                 `json:"right,omitempty"`
+}
+
+func (VkGroupRelationParams) SegmentObjectType() SegmentObjectType {
+	return SegmentObjectTypeVkGroup
+}
+
+func VkGroupMembersRelation(groupId int) SegmentRelation {
+	return NewSegmentRelation(groupId, VkGroupRelationParams{Type: SegmentRelationTypePositive})
+}
+
+// VkGroupActivityRelation selects users who made any of the events in the
+// community during the last days, use VkGroupMembersRelation for members.
+func VkGroupActivityRelation(groupId int, days int, event VkGroupEvent, more ...VkGroupEvent) SegmentRelation {
+	return NewSegmentRelation(groupId, VkGroupRelationParams{
+		Type:   SegmentRelationTypePositive,
+		Events: append([]VkGroupEvent{event}, more...),
+		Left:   days,
+	})
+}
+
+type AppEvent string
+
+const AppEventInstall AppEvent = "install"
+const AppEventLaunch AppEvent = "launch"
+const AppEventPayment AppEvent = "payment"
+
+// AppRelationParams selects app users who made any of Events (any activity
+// when empty) between Left and Right days ago.
+type AppRelationParams struct {
+	Type   SegmentRelationType `json:"type"`
+	Events []AppEvent          `json:"events,omitempty"`
+	Left   int                 `json:"left,omitempty"`
+	Right  int                 `json:"right,omitempty"`
+}
+
+func (AppRelationParams) SegmentObjectType() SegmentObjectType {
+	return SegmentObjectTypeApp
+}
+
+func AppUsersRelation(appId int, days int, events ...AppEvent) SegmentRelation {
+	return NewSegmentRelation(appId, AppRelationParams{
+		Type:   SegmentRelationTypePositive,
+		Events: events,
+		Left:   days,
+	})
+}
+
+// RawSegmentRelationParams keeps params of object types which are not modeled
+// yet, so they survive a decode/encode round trip.
+type RawSegmentRelationParams struct {
+	ObjectType SegmentObjectType
+	Data       json.RawMessage
+}
+
+func (r RawSegmentRelationParams) SegmentObjectType() SegmentObjectType {
+	return r.ObjectType
+}
+
+func (r RawSegmentRelationParams) MarshalJSON() ([]byte, error) {
+	if len(r.Data) == 0 {
+		return []byte("null"), nil
+	}
+	return r.Data, nil
+}
+
+type SegmentRelation struct {
+	Id         int                   `json:"id,omitempty"`
+	ObjectId   int                   `json:"object_id"`
+	ObjectType SegmentObjectType     `json:"object_type"`
+	Params     SegmentRelationParams `json:"params"`
+}
+
+func NewSegmentRelation(objectId int, params SegmentRelationParams) SegmentRelation {
+	return SegmentRelation{
+		ObjectId:   objectId,
+		ObjectType: params.SegmentObjectType(),
+		Params:     params,
+	}
+}
+
+func (r *SegmentRelation) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Id         int               `json:"id"`
+		ObjectId   int               `json:"object_id"`
+		ObjectType SegmentObjectType `json:"object_type"`
+		Params     json.RawMessage   `json:"params"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	r.Id = raw.Id
+	r.ObjectId = raw.ObjectId
+	r.ObjectType = raw.ObjectType
+
+	factory, ok := segmentRelationParamsFactories[raw.ObjectType]
+	if !ok || len(raw.Params) == 0 || string(raw.Params) == "null" {
+		r.Params = RawSegmentRelationParams{ObjectType: raw.ObjectType, Data: raw.Params}
+		return nil
+	}
+
+	params := factory()
+	if err := json.Unmarshal(raw.Params, params); err != nil {
+		return err
+	}
+
+	// factories return pointers to decode into, keep the value like the
+	// callers build it with NewSegmentRelation
+	r.Params = reflect.ValueOf(params).Elem().Interface().(SegmentRelationParams)
+	return nil
+}
+
+type SegmentUser struct {
+	Id       int    `json:"id"`
+	Type     string `json:"type"`
+	Username string `json:"username"`
+}
+
+type SegmentStatus string
+
+const SegmentStatusBuilding SegmentStatus = "building"
+const SegmentStatusReady SegmentStatus = "ready"
+const SegmentStatusFailed SegmentStatus = "failed"
+
+// Segment is a remarketing audience. PassCondition is the number of relations
+// a user has to match to get into the segment, 1 means any of them.
+//
+// Lookalike segments are built from SourceSegmentId or SourceUsersListId and
+// can't be used in targetings until their Status is ready.
+type Segment struct {
+	Id                int               `json:"id"`
+	Name              string            `json:"name"`
+	CampaignIds       []int             `json:"campaign_ids"`
+	Flags             []string          `json:"flags"`
+	PassCondition     int               `json:"pass_condition"`
+	Relations         []SegmentRelation `json:"relations"`
+	RelationsCount    int               `json:"relations_count"`
+	Users             []SegmentUser     `json:"users"`
+	Status            SegmentStatus     `json:"status,omitempty"`
+	SourceSegmentId   int               `json:"source_segment_id,omitempty"`
+	SourceUsersListId int               `json:"source_users_list_id,omitempty"`
+	AudienceSize      int               `json:"audience_size,omitempty"`
+	Created           *DateTime         `json:"created,omitempty"`
+	Updated           *DateTime         `json:"updated,omitempty"`
+}
+
+func (s Segment) IsLookalike() bool {
+	return s.SourceSegmentId != 0 || s.SourceUsersListId != 0
+}
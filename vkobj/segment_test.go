@@ -0,0 +1,121 @@
+package vkobj
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSegmentRelationJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		params SegmentRelationParams
+	}{
+		{
+			name:   "users list",
+			json:   `{"id":1,"object_id":10,"object_type":"remarketing_users_list","params":{"type":"positive"}}`,
+			params: UsersListRelationParams{Type: SegmentRelationTypePositive},
+		},
+		{
+			name:   "counter goal",
+			json:   `{"id":2,"object_id":11,"object_type":"remarketing_counter_goal","params":{"type":"negative","left":30,"right":0}}`,
+			params: CounterGoalRelationParams{Type: SegmentRelationTypeNegative, Left: 30},
+		},
+		{
+			name:   "vk group members",
+			json:   `{"object_id":12,"object_type":"remarketing_vk_group","params":{"type":"positive"}}`,
+			params: VkGroupRelationParams{Type: SegmentRelationTypePositive},
+		},
+		{
+			name:   "vk group activity",
+			json:   `{"object_id":12,"object_type":"remarketing_vk_group","params":{"type":"positive","events":["like","repost"],"left":7}}`,
+			params: VkGroupRelationParams{Type: SegmentRelationTypePositive, Events: []VkGroupEvent{VkGroupEventLike, VkGroupEventRepost}, Left: 7},
+		},
+		{
+			name:   "app",
+			json:   `{"object_id":13,"object_type":"remarketing_player","params":{"type":"positive","events":["payment"],"left":14}}`,
+			params: AppRelationParams{Type: SegmentRelationTypePositive, Events: []AppEvent{AppEventPayment}, Left: 14},
+		},
+		{
+			name:   "unknown object type",
+			json:   `{"object_id":14,"object_type":"remarketing_new_thing","params":{"type":"positive","foo":[1,2]}}`,
+			params: RawSegmentRelationParams{ObjectType: "remarketing_new_thing", Data: json.RawMessage(`{"type":"positive","foo":[1,2]}`)},
+		},
+		{
+			name:   "null params",
+			json:   `{"object_id":15,"object_type":"remarketing_counter","params":null}`,
+			params: RawSegmentRelationParams{ObjectType: SegmentObjectTypeCounter, Data: json.RawMessage(`null`)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var relation SegmentRelation
+			if err := json.Unmarshal([]byte(test.json), &relation); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(relation.Params, test.params) {
+				t.Fatalf("got params %#v, want %#v", relation.Params, test.params)
+			}
+			if relation.ObjectType != relation.Params.SegmentObjectType() {
+				t.Fatalf("object type %s, params of %s", relation.ObjectType, relation.Params.SegmentObjectType())
+			}
+
+			out, err := json.Marshal(relation)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != test.json {
+				t.Fatalf("round trip gave %s", out)
+			}
+		})
+	}
+}
+
+func TestSegmentRelationHelpers(t *testing.T) {
+	tests := []struct {
+		relation SegmentRelation
+		want     string
+	}{
+		{
+			relation: VkGroupMembersRelation(1),
+			want:     `{"object_id":1,"object_type":"remarketing_vk_group","params":{"type":"positive"}}`,
+		},
+		{
+			relation: VkGroupActivityRelation(1, 30, VkGroupEventJoin),
+			want:     `{"object_id":1,"object_type":"remarketing_vk_group","params":{"type":"positive","events":["join"],"left":30}}`,
+		},
+		{
+			relation: VkGroupActivityRelation(1, 7, VkGroupEventLike, VkGroupEventComment),
+			want:     `{"object_id":1,"object_type":"remarketing_vk_group","params":{"type":"positive","events":["like","comment"],"left":7}}`,
+		},
+		{
+			relation: AppUsersRelation(2, 7),
+			want:     `{"object_id":2,"object_type":"remarketing_player","params":{"type":"positive","left":7}}`,
+		},
+		{
+			relation: CounterGoal{Id: 3}.SegmentRelation(10, 0),
+			want:     `{"object_id":3,"object_type":"remarketing_counter_goal","params":{"type":"positive","left":10,"right":0}}`,
+		},
+	}
+
+	for _, test := range tests {
+		out, err := json.Marshal(test.relation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != test.want {
+			t.Errorf("got %s, want %s", out, test.want)
+		}
+	}
+}
+
+func TestSegmentRelationBadParams(t *testing.T) {
+	var relation SegmentRelation
+	err := json.Unmarshal([]byte(`{"object_id":1,"object_type":"remarketing_counter","params":{"left":"x"}}`), &relation)
+	if err == nil {
+		t.Fatal("decoded mistyped params")
+	}
+}
@@ -0,0 +1,232 @@
+package vkobj
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+type AgeTargeting struct {
+	AgeList []int `json:"age_list"`
+	Expand  bool  `json:"expand"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (a *AgeTargeting) UnmarshalJSON(b []byte) error {
+	type plain AgeTargeting
+	return unmarshalWithExtra(b, (*plain)(a), &a.Extra)
+}
+
+func (a AgeTargeting) MarshalJSON() ([]byte, error) {
+	type plain AgeTargeting
+	return marshalWithExtra(plain(a), a.Extra)
+}
+
+func AgeList(from int, to int) []int {
+	var s []int
+	for i := from; i <= to; i++ {
+		s = append(s, i)
+	}
+
+	return s
+}
+
+var DefaultAgeList = AgeList(12, 75)
+
+type FulltimeTargetingFlag = string
+
+const FulltimeTargetingFlagUseHolidaysMoving = "use_holidays_moving"
+const FulltimeTargetingFlagCrossTimezone = "cross_timezone"
+
+type FulltimeTargeting struct {
+	Flags []FulltimeTargetingFlag `json:"flags"`
+	Fri   []int                   `json:"fri"`
+	Mon   []int                   `json:"mon"`
+	Sat   []int                   `json:"sat"`
+	Sun   []int                   `json:"sun"`
+	Thu   []int                   `json:"thu"`
+	Tue   []int                   `json:"tue"`
+	Wed   []int                   `json:"wed"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (f *FulltimeTargeting) UnmarshalJSON(b []byte) error {
+	type plain FulltimeTargeting
+	return unmarshalWithExtra(b, (*plain)(f), &f.Extra)
+}
+
+func (f FulltimeTargeting) MarshalJSON() ([]byte, error) {
+	type plain FulltimeTargeting
+	return marshalWithExtra(plain(f), f.Extra)
+}
+
+var DefaultFulltimeTargetings = FulltimeTargeting{
+	Flags: []FulltimeTargetingFlag{FulltimeTargetingFlagUseHolidaysMoving, FulltimeTargetingFlagCrossTimezone},
+	Fri:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
+	Mon:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
+	Sat:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
+	Sun:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
+	Thu:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
+	Tue:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
+	Wed:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23},
+}
+
+// GeoTargeting selects regions by id, Exclude removes regions (e.g. a city
+// of a selected country).
+type GeoTargeting struct {
+	Regions []int `json:"regions"`
+	Exclude []int `json:"exclude,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (g *GeoTargeting) UnmarshalJSON(b []byte) error {
+	type plain GeoTargeting
+	return unmarshalWithExtra(b, (*plain)(g), &g.Extra)
+}
+
+func (g GeoTargeting) MarshalJSON() ([]byte, error) {
+	type plain GeoTargeting
+	return marshalWithExtra(plain(g), g.Extra)
+}
+
+// GeoCircle is a point with a radius in meters.
+type GeoCircle struct {
+	Name      string  `json:"name,omitempty"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	Radius    int     `json:"radius"`
+}
+
+type LocalGeoType string
+
+const LocalGeoTypeAll LocalGeoType = "all"
+const LocalGeoTypeLive LocalGeoType = "live"
+const LocalGeoTypeWork LocalGeoType = "work"
+const LocalGeoTypeVisit LocalGeoType = "visit"
+
+// LocalGeoTargeting selects users near Circles, who live, work or visited
+// the places during the last Period days depending on Type.
+type LocalGeoTargeting struct {
+	Circles []GeoCircle  `json:"circles"`
+	Exclude []GeoCircle  `json:"exclude,omitempty"`
+	Type    LocalGeoType `json:"type,omitempty"`
+	Period  int          `json:"period,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (g *LocalGeoTargeting) UnmarshalJSON(b []byte) error {
+	type plain LocalGeoTargeting
+	return unmarshalWithExtra(b, (*plain)(g), &g.Extra)
+}
+
+func (g LocalGeoTargeting) MarshalJSON() ([]byte, error) {
+	type plain LocalGeoTargeting
+	return marshalWithExtra(plain(g), g.Extra)
+}
+
+type Sex string
+
+const SexMale Sex = "male"
+const SexFemale Sex = "female"
+
+type MobileType string
+
+const MobileTypeSmartphones MobileType = "smartphones"
+const MobileTypeTablets MobileType = "tablets"
+
+type DeviceType string
+
+const DeviceTypeDesktop DeviceType = "desktop"
+const DeviceTypeMobile DeviceType = "mobile"
+const DeviceTypeTablet DeviceType = "tablet"
+const DeviceTypeTv DeviceType = "tv"
+
+// Targetings is the audience of an ad group. Ids of interests, regions, OS,
+// operators and so on come from GetTargetingsTree and GetRegions.
+//
+// Fields which are not modeled are kept in Extra and sent back unchanged, so
+// updating a fetched ad group never drops targetings set in the web
+// interface.
+type Targetings struct {
+	Age             AgeTargeting       `json:"age,omitempty"`
+	Fulltime        FulltimeTargeting  `json:"fulltime,omitempty"`
+	Geo             GeoTargeting       `json:"geo,omitempty"`
+	LocalGeo        *LocalGeoTargeting `json:"local_geo,omitempty"`
+	Interests       []int              `json:"interests,omitempty"`
+	InterestsSocDem []int              `json:"interests_soc_dem,omitempty"`
+	InterestsStable []int              `json:"interests_stable,omitempty"`
+	Income          []int              `json:"income,omitempty"`
+	Language        []int              `json:"language,omitempty"`
+	Pads            []int              `json:"pads,omitempty"`
+	Segments        []int              `json:"segments,omitempty"`
+	Sex             []Sex              `json:"sex,omitempty"`
+	DeviceTypes     []DeviceType       `json:"device_types,omitempty"`
+	MobileTypes     []MobileType       `json:"mobile_types,omitempty"`
+	MobileOs        []int              `json:"mobile_os,omitempty"`
+	MobileVendors   []int              `json:"mobile_vendors,omitempty"`
+	MobileOperators []int              `json:"mobile_operators,omitempty"`
+	Groups          []int              `json:"groups,omitempty"`
+	GroupsNot       []int              `json:"groups_not,omitempty"`
+	Apps            []int              `json:"apps,omitempty"`
+	AppsNot         []int              `json:"apps_not,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (t *Targetings) UnmarshalJSON(b []byte) error {
+	type plain Targetings
+	return unmarshalWithExtra(b, (*plain)(t), &t.Extra)
+}
+
+func (t Targetings) MarshalJSON() ([]byte, error) {
+	type plain Targetings
+	return marshalWithExtra(plain(t), t.Extra)
+}
+
+// unmarshalWithExtra decodes b into v and collects the object keys v doesn't
+// model into extra.
+func unmarshalWithExtra(b []byte, v interface{}, extra *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	for _, field := range jsonFields(reflect.TypeOf(v).Elem()) {
+		delete(raw, field.name)
+	}
+
+	*extra = nil
+	if len(raw) > 0 {
+		*extra = raw
+	}
+
+	return nil
+}
+
+// marshalWithExtra encodes v adding the extra keys v doesn't set itself.
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	for key, val := range extra {
+		if _, ok := raw[key]; !ok {
+			raw[key] = val
+		}
+	}
+
+	return json.Marshal(raw)
+}
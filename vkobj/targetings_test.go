@@ -0,0 +1,40 @@
+package vkobj
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTargetingsKeepUnknownFields(t *testing.T) {
+	// the struct valued targetings are always encoded, so every input has them
+	const fulltime = `"fulltime":{"flags":[],"fri":[1],"mon":[],"sat":[],"sun":[],"thu":[],"tue":[],"wed":[]`
+	tests := []string{
+		`{"age":{"age_list":[18,19],"expand":true,"foo":1},` + fulltime + `},"geo":{"regions":[1]}}`,
+		`{"age":{"age_list":null,"expand":false},` + fulltime + `,"bar":"x"},"geo":{"regions":[1]}}`,
+		`{"age":{"age_list":null,"expand":false},` + fulltime + `},"geo":{"baz":[2],"regions":[1]},"local_geo":{"circles":[],"qux":true},"unknown":{"a":1}}`,
+	}
+
+	for _, in := range tests {
+		var targetings Targetings
+		if err := json.Unmarshal([]byte(in), &targetings); err != nil {
+			t.Fatalf("unmarshal %s: %v", in, err)
+		}
+
+		out, err := json.Marshal(targetings)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if canonicalJson(out) != canonicalJson([]byte(in)) {
+			t.Errorf("round trip of %s gave %s", in, out)
+		}
+	}
+}
+
+// canonicalJson sorts the object keys of b.
+func canonicalJson(b []byte) string {
+	var v interface{}
+	json.Unmarshal(b, &v)
+	b, _ = json.Marshal(v)
+	return string(b)
+}
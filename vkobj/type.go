@@ -31,24 +31,73 @@ func (d Date) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + time.Time(d).Format("2006-01-02") + `"`), nil
 }
 
+func (d Date) String() string {
+	return time.Time(d).Format("2006-01-02")
+}
+
+const dateTimeLayout = "2006-01-02 15:04:05"
+
+// DateTime is a timestamp of the api. The api sends timestamps without a
+// zone, so DateTime keeps only the wall clock (stored as UTC) and the zone
+// is supplied when converting, see Api.Location.
 type DateTime time.Time
 
+// NewDateTime returns the wall clock of t in loc.
+func NewDateTime(t time.Time, loc *time.Location) DateTime {
+	wall := t.In(loc)
+	return DateTime(time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), time.UTC))
+}
+
+func ParseDateTime(value string) (DateTime, error) {
+	t, err := time.Parse(dateTimeLayout, value)
+	return DateTime(t), err
+}
+
+func DateTimeRef(d DateTime) *DateTime {
+	return &d
+}
+
+// Time returns the instant of the wall clock read in loc.
+func (d DateTime) Time(loc *time.Location) time.Time {
+	t := time.Time(d)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// Add moves the wall clock by duration.
+func (d DateTime) Add(duration time.Duration) DateTime {
+	return DateTime(time.Time(d).Add(duration))
+}
+
+func (d DateTime) Before(o DateTime) bool {
+	return time.Time(d).Before(time.Time(o))
+}
+
+func (d DateTime) IsZero() bool {
+	return time.Time(d).IsZero()
+}
+
+// String formats the timestamp the way the api expects it in filters like
+// _updated__gt.
+func (d DateTime) String() string {
+	return time.Time(d).Format(dateTimeLayout)
+}
+
 func (d *DateTime) UnmarshalJSON(b []byte) error {
 	value := strings.Trim(string(b), `"`) //get rid of "
 	if value == "" || value == "null" {
 		return nil
 	}
 
-	t, err := time.Parse("2006-01-02 15:04:05", value) //parse time
+	t, err := ParseDateTime(value)
 	if err != nil {
 		return err
 	}
-	*d = DateTime(t)
+	*d = t
 	return nil
 }
 
 func (d DateTime) MarshalJSON() ([]byte, error) {
-	return []byte(`"` + time.Time(d).Format("2006-01-02 15:04:05") + `"`), nil
+	return []byte(`"` + d.String() + `"`), nil
 }
 
 type Float64 float64
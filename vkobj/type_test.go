@@ -0,0 +1,71 @@
+package vkobj
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateTimeJSON(t *testing.T) {
+	tests := []string{
+		`{"created":"2023-05-01 10:20:30"}`,
+		`{"created":"2023-12-31 23:59:59"}`,
+		`{}`,
+	}
+
+	for _, in := range tests {
+		var v struct {
+			Created *DateTime `json:"created,omitempty"`
+		}
+		if err := json.Unmarshal([]byte(in), &v); err != nil {
+			t.Fatalf("unmarshal %s: %v", in, err)
+		}
+
+		out, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != in {
+			t.Errorf("round trip of %s gave %s", in, out)
+		}
+	}
+
+	var d DateTime
+	if err := json.Unmarshal([]byte(`"2023-05-01T10:20:30Z"`), &d); err == nil {
+		t.Fatal("decoded a timestamp in another layout")
+	}
+}
+
+func TestDateTimeZones(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	instant := time.Date(2023, 5, 1, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		loc  *time.Location
+		want string
+	}{
+		{loc: time.UTC, want: "2023-05-01 22:30:00"},
+		{loc: moscow, want: "2023-05-02 01:30:00"},
+		{loc: time.FixedZone("", -5*60*60), want: "2023-05-01 17:30:00"},
+	}
+
+	for _, test := range tests {
+		d := NewDateTime(instant, test.loc)
+		if d.String() != test.want {
+			t.Errorf("%s: got %s, want %s", test.loc, d, test.want)
+		}
+
+		parsed, err := ParseDateTime(d.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := parsed.Time(test.loc); !got.Equal(instant) {
+			t.Errorf("%s: %s read back as %s", test.loc, d, got)
+		}
+	}
+
+	d, _ := ParseDateTime("2023-05-01 10:00:00")
+	if !d.Before(d.Add(time.Second)) || d.Add(-time.Second).String() != "2023-05-01 09:59:59" {
+		t.Errorf("unexpected arithmetic on %s", d)
+	}
+}